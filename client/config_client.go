@@ -21,23 +21,58 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	f "github.com/ForgeRock/configsaver/internal/fileutils"
 	pb "github.com/ForgeRock/configsaver/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// How many times to try a call that fails with a transient error
+	maxAttempts = 10
+	// How long to wait before retrying
+	retryDelay = time.Second * 10
 )
 
 type clientCtx struct {
 	server          string
+	profile         string
 	conn            *grpc.ClientConn
 	configDirectory string
 	fileUtil        *f.FileUtil
 	grpc            pb.ConfigSaverClient
+	// serializes scanning and uploading local changes with applying server changes
+	lock sync.Mutex
+	// the server commit the local configuration is based on. Empty if not known.
+	commitId string
+	// set if the config directory only holds product configuration, so files can be deleted to match the server
+	dedicated bool
+	// who is making the changes, recorded in the server's commits
+	podName     string
+	authorName  string
+	authorEmail string
+	// the compression the client prefers for tar files, and the compression it sends updates with. Updates
+	// are not compressed until the server says it accepts the preferred compression.
+	compression       f.Compression
+	uploadCompression f.Compression
+	// set if the server does not have the streaming calls
+	noStreaming atomic.Bool
 }
 
 var kacp = keepalive.ClientParameters{
@@ -48,10 +83,15 @@ var kacp = keepalive.ClientParameters{
 
 // With no args we get the config from the server and exit.
 // with one arg (the time in seconds) we scan for changes and upload to the server
+// The sync subcommand also applies changes made on the server, so replicas of a product converge.
+// The promote subcommand copies the configuration of one profile to another on the server.
 func main() {
 
-	if len(os.Args) > 2 {
-		log.Fatalf("Usage: %s [scanSeconds]", os.Args[0])
+	promote := len(os.Args) > 1 && os.Args[1] == "promote"
+	syncMode := len(os.Args) == 3 && os.Args[1] == "sync"
+	if len(os.Args) > 2 && !promote && !syncMode {
+		log.Fatalf("Usage: %s [scanSeconds]\n       %s sync scanSeconds\n       %s promote [-dry-run] fromProfile toProfile [files...]",
+			os.Args[0], os.Args[0], os.Args[0])
 	}
 
 	// where to save the config
@@ -60,8 +100,22 @@ func main() {
 	configProduct := f.GetEnvOrDefault("CONFIG_PRODUCT", "am")
 	// The config server address:port
 	server := f.GetEnvOrDefault("CONFIG_SERVER", "localhost:50051")
+	// Optional config profile (example, cdk). Defaults to the server's default profile for the product.
+	configProfile := f.GetEnvOrDefault("CONFIG_PROFILE", "")
+	// Optional commit, branch or tag to get the configuration from. Defaults to the server's current configuration.
+	configCommit := f.GetEnvOrDefault("CONFIG_COMMIT", "")
+	// The pod the client runs in, and the optional author of the changes, for the server's commit messages
+	hostname, _ := os.Hostname()
+	podName := f.GetEnvOrDefault("POD_NAME", hostname)
+	authorName := f.GetEnvOrDefault("CONFIG_AUTHOR_NAME", "")
+	authorEmail := f.GetEnvOrDefault("CONFIG_AUTHOR_EMAIL", "")
+	// How to compress the tar files sent to and from the server
+	compression, err := f.ParseCompression(f.GetEnvOrDefault("CONFIG_COMPRESSION", "zstd"))
+	if err != nil {
+		log.Fatalf("Invalid CONFIG_COMPRESSION: %v", err)
+	}
 
-	log.Printf("config_client starting. product: %s,  configDir: %s\n", configProduct, configDir)
+	log.Printf("config_client starting. product: %s, profile: %s, configDir: %s\n", configProduct, configProfile, configDir)
 
 	// todo: Move to NewFileUtil
 	if _, err := os.Stat(configDir); os.IsNotExist(err) {
@@ -71,6 +125,17 @@ func main() {
 		}
 	}
 
+	fileUtil := f.NewFileUtil(configDir)
+	dedicated, err := fileUtil.Dedicated()
+	if err != nil {
+		log.Printf("could not check the config directory %s: %v", configDir, err)
+	}
+	if !dedicated {
+		log.Printf("%s has files that are not product configuration, and no %s file. Files are not deleted to match "+
+			"the server, and files deleted while the client was not running are not deleted on the server. "+
+			"Use an empty directory for the configuration.", configDir, f.MarkerFile)
+	}
+
 	log.Printf("Waiting for server connection %s\n", server)
 	// Set up a connection to the server.
 	conn, err := grpc.Dial(server, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithKeepaliveParams(kacp))
@@ -82,97 +147,627 @@ func main() {
 
 	client := clientCtx{
 		server:          server,
+		profile:         configProfile,
 		configDirectory: configDir,
-		fileUtil:        f.NewFileUtil(configDir),
+		fileUtil:        fileUtil,
+		dedicated:       dedicated,
 		conn:            conn,
 		grpc:            c,
+		podName:         podName,
+		authorName:      authorName,
+		authorEmail:     authorEmail,
+		compression:     compression,
+	}
+
+	if promote {
+		client.promoteConfig(configProduct, os.Args[2:])
+		os.Exit(0)
 	}
 
 	// If there is only one arg, read the config from the server and exit
 	if len(os.Args) == 1 {
-		client.getConfigFromServer(configProduct)
+		client.getConfigFromServer(configProduct, configCommit)
 		os.Exit(0)
 	}
 
 	// There is more than org, so we want to iterate looking for changes to send to the server.
-	scanSeconds, err := strconv.Atoi(os.Args[1])
+	scanArg := os.Args[len(os.Args)-1]
+	scanSeconds, err := strconv.Atoi(scanArg)
 	if err != nil || scanSeconds < 1 || scanSeconds > 120 {
-		log.Fatalf("Invalid scanSeconds: %s. Must be between 1 and 120", os.Args[2])
+		log.Fatalf("Invalid scanSeconds: %s. Must be between 1 and 120", scanArg)
 	}
 
 	scanDuration := time.Duration(scanSeconds) * time.Second
+	if syncMode {
+		// Start from the server configuration, then keep up with changes made elsewhere
+		client.commitId = client.getConfigFromServer(configProduct, "")
+		// Record the files before watching, so the server's changes are not mistaken for local changes and skipped
+		client.scanInitialFiles()
+		go client.applyServerChanges(configProduct, client.commitId)
+	} else {
+		// Upload changes made while the client was not running
+		client.uploadLocalChanges(configProduct)
+		client.scanInitialFiles()
+	}
 	client.scanAndSaveToServer(scanDuration, configProduct)
 
 }
 
-func (client *clientCtx) getConfigFromServer(productId string) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*120)
-	defer cancel()
-	r, err := client.grpc.GetConfig(ctx, &pb.GetConfigRequest{ProductId: productId, CommitId: "master"})
-	if err != nil {
-		log.Fatalf("could not get configuration for %s from the server: %v", "am", err)
+// getConfigFromServer writes the product configuration to the config directory, and returns the commit it was read from
+func (client *clientCtx) getConfigFromServer(productId, commitId string) string {
+	var r *pb.GetConfigReply
+	var err error
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*120)
+		r, err = client.getConfig(ctx, &pb.GetConfigRequest{
+			ProductId:         productId,
+			Profile:           client.profile,
+			CommitId:          commitId,
+			AcceptCompression: client.acceptCompression(),
+			Manifest:          client.manifest(),
+		})
+		cancel()
+		if err == nil {
+			break
+		}
+		if !isRetryable(err) || attempt == maxAttempts {
+			log.Fatalf("could not get configuration for %s from the server: %s", productId, describeError(err))
+		}
+		log.Printf("error getting configuration %s. Ill try again", describeError(err))
+		time.Sleep(retryDelay)
 	}
-	log.Printf("Status = %d Error message: %s", r.Status, r.ErrorMessage)
+	log.Printf("Received configuration from commit: %s", r.CommitId)
+	client.negotiateCompression(r.AcceptedCompression)
 	if err := client.fileUtil.UnpackTarBuffer(r.GetConfigTar(), ""); err != nil {
 		log.Fatalf("could not unpack configuration: %v", err)
 	}
+	if err := client.fileUtil.DeleteFiles(r.DeletedFiles, ""); err != nil {
+		log.Fatalf("could not delete files removed on the server: %v", err)
+	}
+	return r.CommitId
 }
 
-// Loops looking for changes to the config directory and uploads to the server
-func (client *clientCtx) scanAndSaveToServer(scanDuration time.Duration, productId string) {
+// applyServerChanges watches for changes to the product configuration made by other clients or on the server,
+// and writes them to the config directory. commitId is the revision the config directory was read from; changes
+// made after it, including any made while reconnecting, are applied.
+func (client *clientCtx) applyServerChanges(productId, commitId string) {
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := client.grpc.WatchConfig(ctx, &pb.WatchConfigRequest{ProductId: productId, Profile: client.profile, CommitId: commitId})
+		for err == nil {
+			var event *pb.ConfigEvent
+			if event, err = stream.Recv(); err == nil {
+				if err = client.applyEvent(productId, event); err == nil {
+					commitId = event.CommitId
+				}
+			}
+		}
+		cancel()
+		if !isRetryable(err) {
+			log.Fatalf("could not watch configuration for %s: %s", productId, describeError(err))
+		}
+		log.Printf("error watching configuration %s. Ill try again", describeError(err))
+		time.Sleep(retryDelay)
+	}
+}
+
+// applyEvent gets the files changed by a server commit, and applies the changes to the config directory
+func (client *clientCtx) applyEvent(productId string, event *pb.ConfigEvent) error {
+	log.Printf("server commit %s changed=%d deleted=%d", event.CommitId, len(event.ChangedFiles), len(event.DeletedFiles))
+	files := make(map[string][]byte)
+	if len(event.ChangedFiles) > 0 {
+		// The checksums are shared with the scan
+		client.lock.Lock()
+		manifest := client.manifest()
+		client.lock.Unlock()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*120)
+		// The server only sends the files that differ from ours
+		r, err := client.getConfig(ctx, &pb.GetConfigRequest{
+			ProductId:         productId,
+			Profile:           client.profile,
+			CommitId:          event.CommitId,
+			AcceptCompression: client.acceptCompression(),
+			Manifest:          manifest,
+		})
+		cancel()
+		if err != nil {
+			return err
+		}
+		all, err := f.ReadTarBuffer(r.ConfigTar)
+		if err != nil {
+			return err
+		}
+		for _, name := range event.ChangedFiles {
+			if data, ok := all[name]; ok {
+				files[name] = data
+			}
+		}
+	}
+
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	skipped, err := client.fileUtil.ApplyChanges(files, event.DeletedFiles)
+	if err != nil {
+		return err
+	}
+	if len(skipped) > 0 {
+		// The local changes will be uploaded by the next scan. Keep the old base commit, so the server
+		// reports a conflict if they overwrite this change.
+		log.Printf("kept local changes to %v", skipped)
+		return nil
+	}
+	client.commitId = event.CommitId
+	return nil
+}
+
+// promoteConfig copies the product configuration from one profile to another on the server, and prints the changes.
+// args are [-dry-run] fromProfile toProfile [files...]
+func (client *clientCtx) promoteConfig(productId string, args []string) {
+	flags := flag.NewFlagSet("promote", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "show the changes without saving them")
+	_ = flags.Parse(args)
+	if flags.NArg() < 2 {
+		log.Fatalf("Usage: %s promote [-dry-run] fromProfile toProfile [files...]", os.Args[0])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*120)
+	defer cancel()
+	r, err := client.grpc.PromoteConfig(ctx, &pb.PromoteConfigRequest{
+		ProductId:   productId,
+		FromProfile: flags.Arg(0),
+		ToProfile:   flags.Arg(1),
+		Files:       flags.Args()[2:],
+		DryRun:      *dryRun,
+	})
+	if err != nil {
+		log.Fatalf("could not promote configuration for %s: %s", productId, describeError(err))
+	}
+
+	for _, file := range r.Files {
+		fmt.Print(file.Patch)
+	}
+	fmt.Printf("\nadded: %d modified: %d deleted: %d from commit %s\n", len(r.AddedFiles), len(r.ModifiedFiles), len(r.DeletedFiles), r.SourceCommitId)
+	switch {
+	case *dryRun:
+		fmt.Println("dry run, nothing was saved")
+	case r.CommitId == "":
+		fmt.Println("nothing to promote")
+	default:
+		fmt.Printf("saved commit %s\n", r.CommitId)
+	}
+}
 
-	// The first pass through scans the initial files. We do this so all the files don't
-	// get flagged as new
+// scanInitialFiles records the files in the config directory, so they don't get flagged as new by the first scan
+func (client *clientCtx) scanInitialFiles() {
+	client.lock.Lock()
 	err := client.fileUtil.ScanFiles()
+	client.lock.Unlock()
 	if err != nil {
 		log.Printf("Error scanning files: %v", err)
 	}
+}
 
-	// now loop looking for changes
+// Loops looking for changes to the config directory and uploads to the server. The initial files must have been
+// scanned first, with scanInitialFiles.
+func (client *clientCtx) scanAndSaveToServer(scanDuration time.Duration, productId string) {
+	// loop looking for changes
 	for {
-		tarBytes := make([]byte, 0)
+		client.saveLocalChanges(productId)
+		time.Sleep(scanDuration)
+	}
+
+}
+
+// saveLocalChanges scans the config directory and uploads any changes to the server
+func (client *clientCtx) saveLocalChanges(productId string) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
 
-		err := client.fileUtil.ScanFiles()
+	tarBytes := make([]byte, 0)
+
+	err := client.fileUtil.ScanFiles()
+	if err != nil {
+		log.Printf("Error scanning files: %v", err)
+	}
+	newOrModifiedFiles := len(client.fileUtil.ModifiedFiles) > 0 || len(client.fileUtil.NewFiles) > 0
+	if newOrModifiedFiles {
+		tarBytes, err = client.fileUtil.TarUpModifiedFiles(client.uploadCompression)
 		if err != nil {
-			log.Printf("Error scanning files: %v", err)
+			log.Printf("Error creating tar: %v", err)
+			client.fileUtil.Requeue()
+			return
 		}
-		newOrModifiedFiles := len(client.fileUtil.ModifiedFiles) > 0 || len(client.fileUtil.NewFiles) > 0
-		if newOrModifiedFiles {
-			tarBytes, err = client.fileUtil.TarUpModifiedFiles()
-			if err != nil {
-				log.Printf("Error creating tar: %v", err)
+		log.Printf("Number files modified = %d  new = %d tar file size=%d\n", len(client.fileUtil.ModifiedFiles), len(client.fileUtil.NewFiles), len(tarBytes))
+	}
+
+	// if there are new files, modified files, or deleted files, then let the server know
+	if newOrModifiedFiles || len(client.fileUtil.DeletedFiles) > 0 {
+		in := &pb.UpdateConfigRequest{
+			CommitId:     client.commitId,
+			ProductId:    productId,
+			Profile:      client.profile,
+			ConfigTar:    tarBytes,
+			DeletedFiles: client.fileUtil.DeletedFiles,
+			PodName:      client.podName,
+			AuthorName:   client.authorName,
+			AuthorEmail:  client.authorEmail,
+		}
+		for attempt := 1; ; attempt++ {
+
+			log.Printf("updating server, modified=%d  new=%d deleted=%d  tar_bytes=%d\n",
+				len(client.fileUtil.ModifiedFiles), len(client.fileUtil.NewFiles), len(client.fileUtil.DeletedFiles), len(tarBytes))
+			// todo: what to do about defer in infinite loop?
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			r, err := client.updateConfig(ctx, in)
+			cancel()
+
+			if err == nil {
+				log.Printf("server saved update, commit: %s", r.CommitId)
+				client.commitId = r.CommitId
+				client.negotiateCompression(r.AcceptedCompression)
+				break
 			}
-			log.Printf("Number files modified = %d  new = %d tar file size=%d\n", len(client.fileUtil.ModifiedFiles), len(client.fileUtil.NewFiles), len(tarBytes))
-		}
-
-		// if there are new files, modified files, or deleted files, then let the server know
-		if newOrModifiedFiles || len(client.fileUtil.DeletedFiles) > 0 {
-			for {
-
-				log.Printf("updating server, modified=%d  new=%d deleted=%d  tar_bytes=%d\n",
-					len(client.fileUtil.ModifiedFiles), len(client.fileUtil.NewFiles), len(client.fileUtil.DeletedFiles), len(tarBytes))
-				// todo: what to do about defer in infinite loop?
-				ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-				r, err := client.grpc.UpdateConfig(ctx, &pb.UpdateConfigRequest{
-					CommitId:     "master",
-					ProductId:    productId,
-					ConfigTar:    tarBytes,
-					DeletedFiles: client.fileUtil.DeletedFiles,
-				})
-				cancel()
-
-				if err != nil {
-					log.Printf("error updating server %v. Ill try again", err)
-				} else {
-					log.Printf("response status %d  %s", r.Status, r.ErrorMessage)
-					break
-				}
-				time.Sleep(time.Second * 10)
+			if details := conflictDetails(err); details != nil && len(details.Conflicts) > 0 {
+				log.Printf("server rejected the update, changes conflict with commit %s: %v", details.CommitId, conflictPaths(err))
+				client.resolveConflict(in, details)
+				break
+			}
+			// The changes are sent again by the next scan, so they are not lost
+			if !isRetryable(err) || attempt == maxAttempts {
+				// Sending the same update again now will fail the same way. Scanning again lets the server changes
+				// be applied in the meantime.
+				log.Printf("server rejected the update %s. Sending it again with the next scan", describeError(err))
+				client.fileUtil.Requeue()
+				break
+			}
+			log.Printf("error updating server %s. Ill try again", describeError(err))
+			time.Sleep(retryDelay)
 
+		}
+	}
+}
+
+// resolveConflict handles an update the server rejected because some files conflict with changes made on the
+// server. The rest of the update is sent again on the same base, so the server merges it. Then the server's
+// version of every other file is applied, and the base moves to the server's commit, so the update is not
+// rejected again. The conflicting files keep their local contents, and are only sent again when they next change.
+// The caller must hold the client lock.
+func (client *clientCtx) resolveConflict(in *pb.UpdateConfigRequest, details *pb.ConflictDetails) {
+	conflicted := make(map[string]bool)
+	for _, c := range details.Conflicts {
+		conflicted[c.Path] = true
+	}
+	paths := make([]string, 0)
+	for _, changed := range []map[string]time.Time{client.fileUtil.ModifiedFiles, client.fileUtil.NewFiles} {
+		for path := range changed {
+			if !conflicted[client.relativePath(path)] {
+				paths = append(paths, path)
 			}
 		}
+	}
+	deleted := make([]string, 0)
+	for _, name := range in.DeletedFiles {
+		if !conflicted[name] {
+			deleted = append(deleted, name)
+		}
+	}
+
+	if len(paths) > 0 || len(deleted) > 0 {
+		tarBytes, err := f.CreateTarBuffer(client.configDirectory, paths, client.uploadCompression)
+		if err != nil {
+			log.Printf("Error creating tar: %v", err)
+			client.fileUtil.Requeue()
+			return
+		}
+		retry := proto.Clone(in).(*pb.UpdateConfigRequest)
+		retry.ConfigTar = tarBytes
+		retry.DeletedFiles = deleted
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		r, err := client.updateConfig(ctx, retry)
+		cancel()
+		if err != nil {
+			log.Printf("server rejected the changes that do not conflict %s. Sending them again with the next scan", describeError(err))
+			client.fileUtil.Requeue()
+			return
+		}
+		log.Printf("server saved the changes that do not conflict, commit: %s", r.CommitId)
+	}
 
-		time.Sleep(scanDuration)
+	commitId, err := client.applyServerFiles(in.ProductId, conflicted)
+	if err != nil {
+		log.Printf("could not read the server's configuration %s. Keeping base commit %s", describeError(err), client.commitId)
+		return
+	}
+	names := make([]string, 0, len(conflicted))
+	for name := range conflicted {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	log.Printf("kept the local version of %v, which conflicts with commit %s. Change them again to replace the server's version",
+		names, commitId)
+	client.commitId = commitId
+}
 
+// applyServerFiles writes the server's current version of the product files to the config directory, apart from
+// the skipped files. It returns the commit the files were read from. Files changed since the last scan are kept,
+// and returned as an error, as the directory does not match the commit. The caller must hold the client lock.
+func (client *clientCtx) applyServerFiles(productId string, skip map[string]bool) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*120)
+	defer cancel()
+	// The server only sends the files that differ from ours
+	r, err := client.getConfig(ctx, &pb.GetConfigRequest{
+		ProductId:         productId,
+		Profile:           client.profile,
+		AcceptCompression: client.acceptCompression(),
+		Manifest:          client.manifest(),
+	})
+	if err != nil {
+		return "", err
+	}
+	files, err := f.ReadTarBuffer(r.ConfigTar)
+	if err != nil {
+		return "", err
+	}
+	for name := range skip {
+		delete(files, name)
+	}
+	deleted := make([]string, 0, len(r.DeletedFiles))
+	for _, name := range r.DeletedFiles {
+		if !skip[name] {
+			deleted = append(deleted, name)
+		}
+	}
+	skipped, err := client.fileUtil.ApplyChanges(files, deleted)
+	if err != nil {
+		return "", err
+	}
+	if len(skipped) > 0 {
+		return "", fmt.Errorf("%v changed since the last scan", skipped)
+	}
+	return r.CommitId, nil
+}
+
+// relativePath returns the path of a file in the config directory, relative to the directory
+func (client *clientCtx) relativePath(path string) string {
+	rpath, err := filepath.Rel(client.configDirectory, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rpath)
+}
+
+// uploadLocalChanges compares the config directory with the server's configuration, and uploads the files the
+// server does not have. Files only on the server were deleted while the client was not running, and are deleted on
+// the server, unless the config directory is empty and has not been read from the server yet.
+func (client *clientCtx) uploadLocalChanges(productId string) {
+	if !client.dedicated {
+		log.Printf("the base commit is not known, updates may overwrite changes made on the server")
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*120)
+	defer cancel()
+	manifest := client.manifest()
+	r, err := client.grpc.CompareManifest(ctx, &pb.CompareManifestRequest{ProductId: productId, Profile: client.profile, Manifest: manifest})
+	if err != nil {
+		if status.Code(err) != codes.Unimplemented {
+			log.Printf("could not compare the config directory with the server: %s", describeError(err))
+		}
+		log.Printf("the base commit is not known, updates may overwrite changes made on the server")
+		return
+	}
+	// Our files, apart from those uploaded below, match this commit. Updates are merged against it.
+	client.commitId = r.CommitId
+	deleted := r.MissingFiles
+	if len(manifest) == 0 && len(deleted) > 0 {
+		log.Printf("the config directory is empty, so the server's %d files are not deleted", len(deleted))
+		deleted = nil
+	}
+	if len(r.ChangedFiles) == 0 && len(deleted) == 0 {
+		return
+	}
+
+	paths := make([]string, 0, len(r.ChangedFiles))
+	for _, name := range r.ChangedFiles {
+		paths = append(paths, filepath.Join(client.configDirectory, name))
+	}
+	tarBytes, err := f.CreateTarBuffer(client.configDirectory, paths, client.uploadCompression)
+	if err != nil {
+		log.Printf("Error creating tar: %v", err)
+		return
+	}
+	log.Printf("uploading %d files that differ from commit %s, and deleting %d", len(paths), r.CommitId, len(deleted))
+	u, err := client.updateConfig(ctx, &pb.UpdateConfigRequest{
+		CommitId:     r.CommitId,
+		ProductId:    productId,
+		Profile:      client.profile,
+		ConfigTar:    tarBytes,
+		DeletedFiles: deleted,
+		PodName:      client.podName,
+		AuthorName:   client.authorName,
+		AuthorEmail:  client.authorEmail,
+	})
+	if err != nil {
+		log.Printf("server rejected the update %s", describeError(err))
+		return
+	}
+	log.Printf("server saved update, commit: %s", u.CommitId)
+	client.commitId = u.CommitId
+	client.negotiateCompression(u.AcceptedCompression)
+}
+
+// manifest returns the path and checksum of each file in the config directory. If the files can not be read, or
+// the directory holds other files, it returns nil and the server sends every file without deleting any.
+func (client *clientCtx) manifest() []*pb.FileHash {
+	if !client.dedicated {
+		return nil
+	}
+	sums, err := client.fileUtil.Manifest()
+	if err != nil {
+		log.Printf("could not hash the config directory: %v", err)
+		return nil
+	}
+	manifest := make([]*pb.FileHash, 0, len(sums))
+	for name, sum := range sums {
+		manifest = append(manifest, &pb.FileHash{Path: name, Sha256: sum})
+	}
+	sort.Slice(manifest, func(i, j int) bool { return manifest[i].Path < manifest[j].Path })
+	return manifest
+}
+
+// getConfig gets the configuration with GetConfigStream, so it may be larger than the gRPC message size limit.
+// Servers without GetConfigStream are asked with GetConfig. The reply has the whole tar file.
+func (client *clientCtx) getConfig(ctx context.Context, in *pb.GetConfigRequest) (*pb.GetConfigReply, error) {
+	if !client.noStreaming.Load() {
+		stream, err := client.grpc.GetConfigStream(ctx, in)
+		var r *pb.GetConfigReply
+		if err == nil {
+			r, err = receiveConfig(stream)
+		}
+		if status.Code(err) != codes.Unimplemented {
+			return r, err
+		}
+		client.noStreaming.Store(true)
+	}
+	return client.grpc.GetConfig(ctx, in)
+}
+
+// receiveConfig reads the header and the tar file from a GetConfigStream call, checking the tar file matches
+// the checksum in the trailer
+func receiveConfig(stream pb.ConfigSaver_GetConfigStreamClient) (*pb.GetConfigReply, error) {
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return nil, fmt.Errorf("the server did not send a header")
+	}
+	r := f.NewChunkReader(func() (f.Chunk, error) {
+		m, err := stream.Recv()
+		if err != nil {
+			return f.Chunk{}, err
+		}
+		switch chunk := m.Chunk.(type) {
+		case *pb.GetConfigChunk_Data:
+			return f.Chunk{Data: chunk.Data}, nil
+		case *pb.GetConfigChunk_Trailer:
+			return f.Chunk{Last: true, Sha256: chunk.Trailer.Sha256, Size: chunk.Trailer.Size}, nil
+		}
+		return f.Chunk{}, fmt.Errorf("expected a data chunk or the trailer from the server")
+	})
+	if header.ConfigTar, err = io.ReadAll(r); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// updateConfig sends an update with UpdateConfigStream, so it may be larger than the gRPC message size limit.
+// Servers without UpdateConfigStream are sent UpdateConfig.
+func (client *clientCtx) updateConfig(ctx context.Context, in *pb.UpdateConfigRequest) (*pb.UpdateConfigReply, error) {
+	if !client.noStreaming.Load() {
+		r, err := client.sendUpdate(ctx, in)
+		if status.Code(err) != codes.Unimplemented {
+			return r, err
+		}
+		client.noStreaming.Store(true)
+	}
+	return client.grpc.UpdateConfig(ctx, in)
+}
+
+// sendUpdate sends the header, the tar file in chunks and the trailer of an UpdateConfigStream call
+func (client *clientCtx) sendUpdate(ctx context.Context, in *pb.UpdateConfigRequest) (*pb.UpdateConfigReply, error) {
+	stream, err := client.grpc.UpdateConfigStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	header := proto.Clone(in).(*pb.UpdateConfigRequest)
+	header.ConfigTar = nil
+	err = stream.Send(&pb.UpdateConfigChunk{Chunk: &pb.UpdateConfigChunk_Header{Header: header}})
+
+	w := f.NewChunkWriter(func(data []byte) error {
+		return stream.Send(&pb.UpdateConfigChunk{Chunk: &pb.UpdateConfigChunk_Data{Data: data}})
+	})
+	if err == nil {
+		_, err = w.Write(in.ConfigTar)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		trailer := &pb.ChunkTrailer{Sha256: w.Sum(), Size: w.Size()}
+		err = stream.Send(&pb.UpdateConfigChunk{Chunk: &pb.UpdateConfigChunk_Trailer{Trailer: trailer}})
+	}
+	// io.EOF means the server ended the call early. CloseAndRecv returns its status.
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return stream.CloseAndRecv()
+}
+
+// acceptCompression returns the compression the client asks the server to send tar files with
+func (client *clientCtx) acceptCompression() []pb.Compression {
+	if client.compression == f.Uncompressed {
+		return nil
+	}
+	return []pb.Compression{pb.Compression(client.compression)}
+}
+
+// negotiateCompression picks the compression for updates, from the compression the server accepts.
+// Older servers do not say, and only read uncompressed tar files.
+func (client *clientCtx) negotiateCompression(accepted []pb.Compression) {
+	client.uploadCompression = f.Uncompressed
+	for _, c := range accepted {
+		if f.Compression(c) == client.compression {
+			client.uploadCompression = client.compression
+		}
+	}
+}
+
+// isRetryable returns true if the error is transient, and the call may succeed if it is retried.
+// Errors such as an unknown product or an invalid tar file will fail every time, and so may a server failure
+// (Internal or Unknown), for example a repository in a bad state.
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// conflictDetails returns the ConflictDetails of an error, or nil if it has none
+func conflictDetails(err error) *pb.ConflictDetails {
+	for _, detail := range status.Convert(err).Details() {
+		if details, ok := detail.(*pb.ConflictDetails); ok {
+			return details
+		}
+	}
+	return nil
+}
+
+// conflictPaths returns the files (and JSON values) listed in the ConflictDetails of an error, if any
+func conflictPaths(err error) []string {
+	paths := make([]string, 0)
+	for _, detail := range status.Convert(err).Details() {
+		if conflicts, ok := detail.(*pb.ConflictDetails); ok {
+			for _, c := range conflicts.Conflicts {
+				if c.JsonPointer != "" {
+					paths = append(paths, c.Path+"#"+c.JsonPointer)
+				} else {
+					paths = append(paths, c.Path)
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// describeError formats a gRPC error with the status code and the reason from the error details.
+func describeError(err error) string {
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return fmt.Sprintf("%s (%s): %s", st.Code(), info.Reason, st.Message())
+		}
+	}
+	return fmt.Sprintf("%s: %s", st.Code(), st.Message())
 }
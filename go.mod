@@ -1,11 +1,14 @@
 module github.com/ForgeRock/configsaver
 
-go 1.17
+go 1.22
 
 require (
+	github.com/klauspost/compress v1.18.0
 	github.com/libgit2/git2go/v31 v31.4.14
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
 	google.golang.org/grpc v1.40.0
 	google.golang.org/protobuf v1.27.1
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -14,5 +17,5 @@ require (
 	golang.org/x/net v0.0.0-20200822124328-c89045814202 // indirect
 	golang.org/x/sys v0.0.0-20201204225414-ed752295db88 // indirect
 	golang.org/x/text v0.3.0 // indirect
-	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
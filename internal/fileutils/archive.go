@@ -0,0 +1,262 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package fileutils
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Default limits on the archives we read, so a small upload can not fill the disk or memory.
+// Override with TAR_MAX_FILES, TAR_MAX_FILE_SIZE and TAR_MAX_TOTAL_SIZE (sizes are in bytes, uncompressed).
+const (
+	defaultMaxFiles     = 10000
+	defaultMaxFileSize  = 10 << 20
+	defaultMaxTotalSize = 100 << 20
+)
+
+type archiveLimits struct {
+	maxFiles     int64
+	maxFileSize  int64
+	maxTotalSize int64
+}
+
+var (
+	limits     archiveLimits
+	limitsOnce sync.Once
+)
+
+// getArchiveLimits returns the limits, read from the environment the first time they are needed
+func getArchiveLimits() archiveLimits {
+	limitsOnce.Do(func() {
+		limits = archiveLimits{
+			maxFiles:     envLimit("TAR_MAX_FILES", defaultMaxFiles),
+			maxFileSize:  envLimit("TAR_MAX_FILE_SIZE", defaultMaxFileSize),
+			maxTotalSize: envLimit("TAR_MAX_TOTAL_SIZE", defaultMaxTotalSize),
+		}
+	})
+	return limits
+}
+
+func envLimit(envVar string, defaultVal int64) int64 {
+	val, err := strconv.ParseInt(GetEnvOrDefault(envVar, strconv.FormatInt(defaultVal, 10)), 10, 64)
+	if err != nil || val < 1 {
+		log.Printf("invalid %s, using the default of %d", envVar, defaultVal)
+		return defaultVal
+	}
+	return val
+}
+
+// UnsafeArchiveError lists the archive entries that were rejected. Nothing is written from an archive
+// with unsafe entries.
+type UnsafeArchiveError struct {
+	// problem keyed by entry name
+	Entries map[string]string
+}
+
+func (e *UnsafeArchiveError) Error() string {
+	entries := make([]string, 0, len(e.Entries))
+	for name, problem := range e.Entries {
+		entries = append(entries, fmt.Sprintf("%s: %s", name, problem))
+	}
+	sort.Strings(entries)
+	return "unsafe archive entries " + strings.Join(entries, ", ")
+}
+
+func (e *UnsafeArchiveError) Unwrap() error {
+	return ErrInvalidArchive
+}
+
+// CheckPaths checks paths sent with an archive, such as the files an update deletes, using the same
+// rules as the archive entries. The cleaned paths are returned.
+func CheckPaths(names []string) ([]string, error) {
+	cleaned := make([]string, 0, len(names))
+	unsafe := make(map[string]string)
+	for _, name := range names {
+		clean, problem := entryPath(name)
+		if problem != "" {
+			unsafe[name] = problem
+			continue
+		}
+		cleaned = append(cleaned, clean)
+	}
+	if len(unsafe) > 0 {
+		return nil, &UnsafeArchiveError{Entries: unsafe}
+	}
+	return cleaned, nil
+}
+
+// archive is the checked contents of a tar file. Paths are relative to the root of the archive.
+type archive struct {
+	files map[string][]byte
+	// symbolic links, with the target relative to the directory of the link
+	symlinks map[string]string
+}
+
+// readArchive reads a tar file, which may be compressed, into memory. The reader is read to the end, so a
+// streamed file's checksum is verified before anything is written. Each entry is checked to be safe to extract
+// under a directory: a relative path that stays inside the directory and not in a .git directory, a regular file,
+// directory or link, with links pointing inside the directory and no entry under a link. The limits on the number of files and their size
+// are enforced as the archive is read.
+func readArchive(r io.Reader) (*archive, error) {
+	a := &archive{files: make(map[string][]byte), symlinks: make(map[string]string)}
+	limits := getArchiveLimits()
+	// The uncompressed archive has room for the files, and the headers and padding of each entry
+	reader, release, err := decompressReader(r, limits.maxTotalSize+limits.maxFiles*4096)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	tarReader := tar.NewReader(reader)
+
+	unsafe := make(map[string]string)
+	var count, total int64
+	// addFile counts a file against the limits. It returns false once the archive is too big to keep reading.
+	addFile := func(name string, size int64) bool {
+		count++
+		total += size
+		switch {
+		case count > limits.maxFiles:
+			unsafe[name] = fmt.Sprintf("archive has more than %d files", limits.maxFiles)
+		case total > limits.maxTotalSize:
+			unsafe[name] = fmt.Sprintf("archive is larger than %d bytes", limits.maxTotalSize)
+		default:
+			return true
+		}
+		return false
+	}
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: could not read next tar header, got error '%w'", ErrInvalidArchive, err)
+		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		name, problem := entryPath(header.Name)
+		if problem != "" && !(header.Typeflag == tar.TypeDir && name == ".") {
+			unsafe[header.Name] = problem
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+		case tar.TypeReg, tar.TypeRegA:
+			if header.Size > limits.maxFileSize {
+				unsafe[header.Name] = fmt.Sprintf("file is larger than %d bytes", limits.maxFileSize)
+				return nil, &UnsafeArchiveError{Entries: unsafe}
+			}
+			if !addFile(header.Name, header.Size) {
+				return nil, &UnsafeArchiveError{Entries: unsafe}
+			}
+			data, err := io.ReadAll(tarReader)
+			if err != nil {
+				return nil, fmt.Errorf("%w: could not read file '%s' from tarball, got error '%w'", ErrInvalidArchive, header.Name, err)
+			}
+			a.files[name] = data
+		case tar.TypeLink:
+			// Hard link targets are relative to the root of the archive, and must be earlier in it
+			target, problem := entryPath(header.Linkname)
+			data, ok := a.files[target]
+			switch {
+			case problem != "":
+				unsafe[header.Name] = "link target " + problem
+			case !ok:
+				unsafe[header.Name] = "link target is not a file in the archive"
+			case !addFile(header.Name, int64(len(data))):
+				return nil, &UnsafeArchiveError{Entries: unsafe}
+			default:
+				a.files[name] = data
+			}
+		case tar.TypeSymlink:
+			if path.IsAbs(header.Linkname) {
+				unsafe[header.Name] = "link target is an absolute path"
+			} else if _, problem := entryPath(path.Join(path.Dir(name), header.Linkname)); problem != "" {
+				unsafe[header.Name] = "link target " + problem
+			} else {
+				a.symlinks[name] = header.Linkname
+			}
+		default:
+			unsafe[header.Name] = "not a regular file, directory or link"
+		}
+	}
+	a.checkLinkParents(unsafe)
+	if len(unsafe) > 0 {
+		return nil, &UnsafeArchiveError{Entries: unsafe}
+	}
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return nil, fmt.Errorf("%w: could not read the end of the tar file, got error '%w'", ErrInvalidArchive, err)
+	}
+	return a, nil
+}
+
+// checkLinkParents adds the entries under a link in the archive to unsafe. A link is checked against where it is
+// in the archive, so an entry written through another link could lead anywhere.
+func (a *archive) checkLinkParents(unsafe map[string]string) {
+	check := func(name string) {
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			if _, ok := a.symlinks[dir]; ok {
+				unsafe[name] = "path is under the link " + dir
+				return
+			}
+		}
+	}
+	for name := range a.files {
+		check(name)
+	}
+	for name := range a.symlinks {
+		check(name)
+	}
+}
+
+// entryPath cleans the path of an archive entry. If the path is not safe, the problem is returned.
+// Older clients name entries from the root directory with a leading "/", so a single leading "/" is removed.
+func entryPath(name string) (string, string) {
+	name = strings.TrimPrefix(name, "/")
+	if name == "" {
+		return "", "path is empty"
+	}
+	if path.IsAbs(name) {
+		return "", "path is absolute"
+	}
+	cleaned := path.Clean(name)
+	if cleaned == "." {
+		return cleaned, "path is not a file"
+	}
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", "path is outside the archive"
+	}
+	for _, part := range strings.Split(cleaned, "/") {
+		if part == ".git" {
+			return "", "path is in a .git directory"
+		}
+	}
+	return cleaned, ""
+}
@@ -0,0 +1,324 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package fileutils
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// entry is a file in a test archive, or a link if link is set. Other types of entry set the tar type flag.
+type entry struct {
+	name, link, data string
+	typeflag         byte
+}
+
+func testArchive(t *testing.T, entries []entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(e.data))}
+		if e.link != "" {
+			header = &tar.Header{Name: e.name, Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: e.link}
+		}
+		if e.typeflag != 0 {
+			header = &tar.Header{Name: e.name, Mode: 0644, Typeflag: e.typeflag, Linkname: e.link}
+		}
+		if err := w.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testRoot returns a root directory, with a product directory to write to, and the directory outside it
+func testRoot(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	outside := filepath.Join(dir, "outside")
+	for _, d := range []string{filepath.Join(root, "product"), outside} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root, outside
+}
+
+// setLimits sets the archive limits for a test, reading them from the environment as the server does
+func setLimits(t *testing.T, limits map[string]string) {
+	t.Helper()
+	for name, value := range limits {
+		t.Setenv(name, value)
+	}
+	limitsOnce = sync.Once{}
+	t.Cleanup(func() { limitsOnce = sync.Once{} })
+}
+
+func checkEmpty(t *testing.T, dir string) {
+	t.Helper()
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) > 0 {
+		t.Errorf("%s has %d files, want none written outside the product", dir, len(files))
+	}
+}
+
+func TestUnpackTarUnsafe(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []entry
+		// links in the product directory before the archive is unpacked, keyed by path
+		existing map[string]string
+		// archive limits, keyed by environment variable
+		limits map[string]string
+	}{
+		{name: "absolute path", entries: []entry{{name: "//secret", data: "x"}}},
+		{name: "path outside", entries: []entry{{name: "../secret", data: "x"}}},
+		{name: "path in .git", entries: []entry{{name: ".git/config", data: "x"}}},
+		{name: "absolute link", entries: []entry{{name: "l", link: "/etc/passwd"}}},
+		{name: "link outside", entries: []entry{{name: "l", link: "../../outside"}}},
+		{name: "character device", entries: []entry{{name: "tty", typeflag: tar.TypeChar}}},
+		{name: "block device", entries: []entry{{name: "sda", typeflag: tar.TypeBlock}}},
+		{name: "hard link outside", entries: []entry{{name: "h", link: "../outside/secret", typeflag: tar.TypeLink}}},
+		{name: "hard link to a file not in the archive", entries: []entry{{name: "h", link: "/etc/passwd", typeflag: tar.TypeLink}}},
+		{
+			name:    "too many files",
+			entries: []entry{{name: "a", data: "x"}, {name: "b", data: "x"}, {name: "c", data: "x"}},
+			limits:  map[string]string{"TAR_MAX_FILES": "2"},
+		},
+		{
+			name:    "too many files with a hard link",
+			entries: []entry{{name: "a", data: "x"}, {name: "b", data: "x"}, {name: "h", link: "a", typeflag: tar.TypeLink}},
+			limits:  map[string]string{"TAR_MAX_FILES": "2"},
+		},
+		{
+			name:    "file too large",
+			entries: []entry{{name: "a", data: "12345"}},
+			limits:  map[string]string{"TAR_MAX_FILE_SIZE": "4"},
+		},
+		{
+			name:    "archive too large",
+			entries: []entry{{name: "a", data: "1234"}, {name: "b", data: "1234"}, {name: "c", data: "1234"}},
+			limits:  map[string]string{"TAR_MAX_TOTAL_SIZE": "10"},
+		},
+		{
+			name: "entry under a link in the archive",
+			entries: []entry{
+				{name: "a/b/f", data: "x"},
+				{name: "a/b/d", link: ".."},
+				{name: "a/b/d/x", link: "../../../outside/secret"},
+			},
+		},
+		{
+			name: "file under a link to a directory inside",
+			entries: []entry{
+				{name: "sub/f", data: "x"},
+				{name: "d", link: "sub"},
+				{name: "d/g", data: "y"},
+			},
+		},
+		{
+			name: "link through another link",
+			entries: []entry{
+				{name: "a/b/d", link: ".."},
+				{name: "a/b/e", link: "d/../../../outside/secret"},
+			},
+		},
+		{
+			name:     "file under an existing link",
+			entries:  []entry{{name: "l/secret", data: "x"}},
+			existing: map[string]string{"l": "../../outside"},
+		},
+		{
+			name:     "link through an existing link",
+			entries:  []entry{{name: "a/l", link: "../up/../secret"}},
+			existing: map[string]string{"up": "../../outside/dir"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLimits(t, tt.limits)
+			root, outside := testRoot(t)
+			for name, target := range tt.existing {
+				if err := os.Symlink(target, filepath.Join(root, "product", name)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			f := NewFileUtil(root)
+			err := f.UnpackTarBuffer(testArchive(t, tt.entries), "product")
+			var unsafe *UnsafeArchiveError
+			if !errors.As(err, &unsafe) {
+				t.Fatalf("UnpackTarBuffer() = %v, want an UnsafeArchiveError", err)
+			}
+			if !errors.Is(err, ErrInvalidArchive) {
+				t.Errorf("UnpackTarBuffer() = %v, want ErrInvalidArchive", err)
+			}
+			checkEmpty(t, outside)
+		})
+	}
+}
+
+func TestUnpackTarLinks(t *testing.T) {
+	root, outside := testRoot(t)
+	f := NewFileUtil(root)
+	entries := []entry{
+		{name: "sub/f", data: "x"},
+		{name: "d", link: "sub"},
+		{name: "sub/g", link: "../sub/f"},
+		{name: "missing", link: "sub/new"},
+	}
+	if err := f.UnpackTarBuffer(testArchive(t, entries), "product"); err != nil {
+		t.Fatalf("UnpackTarBuffer() = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "product", "d", "g"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "x" {
+		t.Errorf("d/g = %q, want the contents of sub/f", data)
+	}
+	checkEmpty(t, outside)
+}
+
+func TestUnpackTarAtLimits(t *testing.T) {
+	setLimits(t, map[string]string{"TAR_MAX_FILES": "3", "TAR_MAX_FILE_SIZE": "4", "TAR_MAX_TOTAL_SIZE": "10"})
+	root, outside := testRoot(t)
+	f := NewFileUtil(root)
+	// the files, file size and total size are each exactly the limit
+	entries := []entry{{name: "a", data: "123"}, {name: "b", data: "1234"}, {name: "h", link: "a", typeflag: tar.TypeLink}}
+	for _, compression := range []Compression{Uncompressed, Gzip, Zstd} {
+		t.Run(compression.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := compressWriter(&buf, compression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = w.Write(testArchive(t, entries)); err != nil {
+				t.Fatal(err)
+			}
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if err = f.UnpackTarBuffer(buf.Bytes(), "product"); err != nil {
+				t.Fatalf("UnpackTarBuffer() = %v, want an archive at the limits unpacked", err)
+			}
+			data, err := os.ReadFile(filepath.Join(root, "product", "h"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "123" {
+				t.Errorf("h = %q, want the contents of a", data)
+			}
+		})
+	}
+	checkEmpty(t, outside)
+}
+
+func TestUnpackTarLeadingSlash(t *testing.T) {
+	root, outside := testRoot(t)
+	f := NewFileUtil(root)
+	// older clients name the entries from the root of the product with a leading /
+	entries := []entry{{name: "/conf/am.json", data: "x"}}
+	if err := f.UnpackTarBuffer(testArchive(t, entries), "product"); err != nil {
+		t.Fatalf("UnpackTarBuffer() = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "product", "conf", "am.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "x" {
+		t.Errorf("conf/am.json = %q, want x", data)
+	}
+	paths, err := CheckPaths([]string{"/conf/am.json"})
+	if err != nil || len(paths) != 1 || paths[0] != "conf/am.json" {
+		t.Errorf("CheckPaths() = %v, %v, want conf/am.json", paths, err)
+	}
+	checkEmpty(t, outside)
+}
+
+func TestWriteFilesLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		// links in the product directory, keyed by path
+		links map[string]string
+		file  string
+		// whether the file can be written, and deleted. Deleting a link removes the link, not its target.
+		writeSafe, deleteSafe bool
+	}{
+		{name: "link to a directory outside", links: map[string]string{"l": "../../outside"}, file: "l/secret"},
+		{name: "link to a file outside", links: map[string]string{"l": "../../outside/secret"}, file: "l", deleteSafe: true},
+		{name: "link through a link", links: map[string]string{"up": "../../outside/dir", "a": "up/../.."}, file: "a/secret"},
+		{name: "absolute link", links: map[string]string{"l": "/"}, file: "l/secret"},
+		{name: "link inside", links: map[string]string{"l": "sub"}, file: "l/f", writeSafe: true, deleteSafe: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, outside := testRoot(t)
+			product := filepath.Join(root, "product")
+			if err := os.Mkdir(filepath.Join(product, "sub"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			for name, target := range tt.links {
+				if err := os.Symlink(target, filepath.Join(product, name)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			f := NewFileUtil(root)
+			var unsafe *UnsafeArchiveError
+
+			err := f.WriteFiles(map[string][]byte{tt.file: []byte("x")}, "product")
+			if tt.writeSafe && err != nil {
+				t.Fatalf("WriteFiles() = %v", err)
+			}
+			if !tt.writeSafe && !errors.As(err, &unsafe) {
+				t.Fatalf("WriteFiles() = %v, want an UnsafeArchiveError", err)
+			}
+			checkEmpty(t, outside)
+
+			if err = os.WriteFile(filepath.Join(outside, "secret"), []byte("keep"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			err = f.DeleteFiles([]string{tt.file}, "product")
+			if tt.deleteSafe && err != nil {
+				t.Fatalf("DeleteFiles() = %v", err)
+			}
+			if !tt.deleteSafe && !errors.As(err, &unsafe) {
+				t.Fatalf("DeleteFiles() = %v, want an UnsafeArchiveError", err)
+			}
+			if _, err = os.Stat(filepath.Join(outside, "secret")); err != nil {
+				t.Errorf("file outside the product: %v", err)
+			}
+		})
+	}
+}
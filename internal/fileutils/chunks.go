@@ -0,0 +1,147 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package fileutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// ChunkSize is the most data sent in one chunk of a streamed tar file
+const ChunkSize = 64 * 1024
+
+// ErrChecksumMismatch is returned when streamed data does not match the checksum sent after it
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// A Chunk is a piece of a streamed tar file, or the trailer that ends the stream with the size and SHA-256
+// checksum of all the data.
+type Chunk struct {
+	Data []byte
+	// set on the trailer only
+	Last   bool
+	Sha256 []byte
+	Size   int64
+}
+
+// ChunkWriter splits the data written to it into chunks, passed to send. Close sends the last of the data;
+// the trailer is then made from Sum and Size.
+type ChunkWriter struct {
+	send func([]byte) error
+	buf  []byte
+	hash hash.Hash
+	size int64
+}
+
+func NewChunkWriter(send func([]byte) error) *ChunkWriter {
+	return &ChunkWriter{send: send, buf: make([]byte, 0, ChunkSize), hash: sha256.New()}
+}
+
+func (w *ChunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close sends any data not sent yet
+func (w *ChunkWriter) Close() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	return w.flush()
+}
+
+func (w *ChunkWriter) flush() error {
+	w.hash.Write(w.buf)
+	w.size += int64(len(w.buf))
+	err := w.send(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+// Sum returns the SHA-256 checksum of the data sent
+func (w *ChunkWriter) Sum() []byte {
+	return w.hash.Sum(nil)
+}
+
+// Size returns the number of bytes sent
+func (w *ChunkWriter) Size() int64 {
+	return w.size
+}
+
+// ChunkReader reads the data of a streamed tar file. next returns each chunk in turn. Read returns io.EOF once
+// the trailer is received and matches the data, or an error wrapping ErrChecksumMismatch if it does not.
+type ChunkReader struct {
+	next func() (Chunk, error)
+	data []byte
+	hash hash.Hash
+	size int64
+	err  error
+}
+
+func NewChunkReader(next func() (Chunk, error)) *ChunkReader {
+	return &ChunkReader{next: next, hash: sha256.New()}
+}
+
+func (r *ChunkReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 && r.err == nil {
+		chunk, err := r.next()
+		switch {
+		case err == io.EOF:
+			r.err = fmt.Errorf("the stream ended without a checksum: %w", io.ErrUnexpectedEOF)
+		case err != nil:
+			r.err = err
+		case chunk.Last:
+			r.err = r.check(chunk)
+		default:
+			r.hash.Write(chunk.Data)
+			r.size += int64(len(chunk.Data))
+			r.data = chunk.Data
+		}
+	}
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// check compares the data received with the trailer, and returns io.EOF if they match
+func (r *ChunkReader) check(trailer Chunk) error {
+	sum := r.hash.Sum(nil)
+	if trailer.Size != r.size || !bytes.Equal(trailer.Sha256, sum) {
+		return fmt.Errorf("%w: received %d bytes with sha256 %x, the sender sent %d bytes with sha256 %x",
+			ErrChecksumMismatch, r.size, sum, trailer.Size, trailer.Sha256)
+	}
+	return io.EOF
+}
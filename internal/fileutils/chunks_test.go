@@ -0,0 +1,92 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package fileutils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// sendChunks writes data through a ChunkWriter, and returns the chunks it sends followed by the trailer
+func sendChunks(t *testing.T, data []byte) []Chunk {
+	t.Helper()
+	var chunks []Chunk
+	w := NewChunkWriter(func(b []byte) error {
+		// the writer reuses its buffer, as a gRPC stream may once the chunk is sent
+		chunks = append(chunks, Chunk{Data: append([]byte(nil), b...)})
+		return nil
+	})
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return append(chunks, Chunk{Last: true, Sha256: w.Sum(), Size: w.Size()})
+}
+
+// receiveChunks reads the chunks with a ChunkReader
+func receiveChunks(chunks []Chunk) ([]byte, error) {
+	r := NewChunkReader(func() (Chunk, error) {
+		if len(chunks) == 0 {
+			return Chunk{}, io.EOF
+		}
+		chunk := chunks[0]
+		chunks = chunks[1:]
+		return chunk, nil
+	})
+	return io.ReadAll(r)
+}
+
+func TestChunksRoundTrip(t *testing.T) {
+	data := make([]byte, 2*ChunkSize+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	chunks := sendChunks(t, data)
+	// two full chunks, the rest, and the trailer
+	if len(chunks) != 4 {
+		t.Errorf("sent %d chunks, want 4", len(chunks))
+	}
+	got, err := receiveChunks(chunks)
+	if err != nil {
+		t.Fatalf("ReadAll() = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("received %d bytes, want the %d bytes sent", len(got), len(data))
+	}
+}
+
+func TestChunksCorrupted(t *testing.T) {
+	chunks := sendChunks(t, bytes.Repeat([]byte("x"), ChunkSize+1))
+	chunks[1].Data[0] = 'y'
+	if _, err := receiveChunks(chunks); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("ReadAll() = %v, want ErrChecksumMismatch", err)
+	}
+}
+
+func TestChunksNoTrailer(t *testing.T) {
+	chunks := sendChunks(t, bytes.Repeat([]byte("x"), ChunkSize+1))
+	_, err := receiveChunks(chunks[:len(chunks)-1])
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadAll() = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
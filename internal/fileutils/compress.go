@@ -0,0 +1,154 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package fileutils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the format a tar file is compressed with. The values match the Compression enum in the protocol.
+type Compression int32
+
+const (
+	Uncompressed Compression = iota
+	Gzip
+	Zstd
+)
+
+// Magic numbers at the start of compressed data
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+func (c Compression) String() string {
+	switch c {
+	case Uncompressed:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Zstd:
+		return "zstd"
+	}
+	return fmt.Sprintf("compression(%d)", int32(c))
+}
+
+// ParseCompression returns the compression named by s: none, gzip or zstd
+func ParseCompression(s string) (Compression, error) {
+	switch strings.ToLower(s) {
+	case "none", "":
+		return Uncompressed, nil
+	case "gzip":
+		return Gzip, nil
+	case "zstd":
+		return Zstd, nil
+	}
+	return Uncompressed, fmt.Errorf("unknown compression %q. Must be one of none, gzip or zstd", s)
+}
+
+// DetectCompression returns the compression of a tar file, from the first bytes of the file
+func DetectCompression(buf []byte) Compression {
+	switch {
+	case bytes.HasPrefix(buf, gzipMagic):
+		return Gzip
+	case bytes.HasPrefix(buf, zstdMagic):
+		return Zstd
+	}
+	return Uncompressed
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// compressWriter returns a writer that compresses to w. The compressed data is only complete once the
+// writer is closed. Closing it does not close w.
+func compressWriter(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case Uncompressed:
+		return nopWriteCloser{w}, nil
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	}
+	return nil, fmt.Errorf("unknown compression %v", c)
+}
+
+// decompressReader returns a reader of the uncompressed contents of r, detecting the compression, and a function
+// that releases the reader. Reading more than limit bytes fails, so a small compressed file can not expand
+// without end.
+func decompressReader(r io.Reader, limit int64) (io.Reader, func(), error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, nil, fmt.Errorf("%w: could not read the tar file, got error '%w'", ErrInvalidArchive, err)
+	}
+	var reader io.Reader = buffered
+	release := func() {}
+	switch DetectCompression(magic) {
+	case Gzip:
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: could not create gzip reader, got error '%w'", ErrInvalidArchive, err)
+		}
+		reader = gzipReader
+	case Zstd:
+		zstdReader, err := zstd.NewReader(reader, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(uint64(limit)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: could not create zstd reader, got error '%w'", ErrInvalidArchive, err)
+		}
+		reader = zstdReader
+		release = zstdReader.Close
+	}
+	return &limitedReader{reader: reader, remaining: limit, limit: limit}, release, nil
+}
+
+// limitedReader fails if there are more than limit bytes to read
+type limitedReader struct {
+	reader           io.Reader
+	remaining, limit int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// Input of exactly the limit is allowed, so only fail if there is more
+		var probe [1]byte
+		n, err := l.reader.Read(probe[:])
+		if n > 0 {
+			return 0, fmt.Errorf("archive expands to more than %d bytes", l.limit)
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.reader.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,127 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package fileutils
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTarRoundTrip(t *testing.T) {
+	archives := []struct {
+		name  string
+		files map[string][]byte
+	}{
+		{name: "single small file", files: map[string][]byte{"a": []byte("x")}},
+		{
+			name: "config",
+			files: map[string][]byte{
+				"am.json":          []byte(`{"a": 1}`),
+				"services/am.json": bytes.Repeat([]byte(`{"b": 2}`), 1000),
+				"empty.json":       {},
+			},
+		},
+	}
+	for _, a := range archives {
+		root := t.TempDir()
+		paths := make([]string, 0, len(a.files))
+		for name, data := range a.files {
+			path := filepath.Join(root, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			paths = append(paths, path)
+		}
+		for _, compression := range []Compression{Uncompressed, Gzip, Zstd} {
+			create := map[string]func() ([]byte, error){
+				"CreateTarBuffer":          func() ([]byte, error) { return CreateTarBuffer(root, paths, compression) },
+				"CreateTarBufferFromFiles": func() ([]byte, error) { return CreateTarBufferFromFiles(a.files, compression) },
+			}
+			for fn, createTar := range create {
+				buf, err := createTar()
+				if err != nil {
+					t.Fatalf("%s %s %s: %v", a.name, compression, fn, err)
+				}
+				if got := DetectCompression(buf); got != compression {
+					t.Errorf("%s %s %s: DetectCompression() = %s", a.name, compression, fn, got)
+				}
+				files, err := ReadTarBuffer(buf)
+				if err != nil {
+					t.Fatalf("%s %s %s: ReadTarBuffer() = %v", a.name, compression, fn, err)
+				}
+				if len(files) != len(a.files) {
+					t.Errorf("%s %s %s: read %d files, want %d", a.name, compression, fn, len(files), len(a.files))
+				}
+				for name, data := range a.files {
+					if !bytes.Equal(files[name], data) {
+						t.Errorf("%s %s %s: %s = %q, want %q", a.name, compression, fn, name, files[name], data)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDecompressReaderLimit(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 4096)
+	for _, compression := range []Compression{Uncompressed, Gzip, Zstd} {
+		var buf bytes.Buffer
+		w, err := compressWriter(&buf, compression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		tests := []struct {
+			limit   int64
+			wantErr bool
+		}{
+			{limit: 4097},
+			{limit: 4096},
+			{limit: 4095, wantErr: true},
+		}
+		for _, tt := range tests {
+			reader, release, err := decompressReader(bytes.NewReader(buf.Bytes()), tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(reader)
+			release()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("%s with limit %d: ReadAll() read %d bytes, want an error", compression, tt.limit, len(got))
+				}
+				continue
+			}
+			if err != nil || !bytes.Equal(got, data) {
+				t.Errorf("%s with limit %d: ReadAll() = %d bytes, %v, want the %d bytes", compression, tt.limit, len(got), err, len(data))
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package fileutils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Files modified this soon before they were hashed may be written again without their modification time changing,
+// so their checksum is not trusted until a later scan reads them again.
+const racyWindow = 2 * time.Second
+
+// fileState is the size, modification time and checksum of a file when it was hashed
+type fileState struct {
+	size    int64
+	modTime time.Time
+	sha256  []byte
+	// set if the file was modified within racyWindow of being hashed
+	racy bool
+}
+
+// fileState returns the state of the file. The cached checksum is used if the file has the same size and
+// modification time as when it was hashed, otherwise the file is read and the cache updated.
+func (f *FileUtil) fileState(path string, info fs.FileInfo) (fileState, error) {
+	if cached, ok := f.hashes[path]; ok && !cached.racy && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached, nil
+	}
+	hashedAt := time.Now()
+	sum, err := hashFile(path)
+	if err != nil {
+		return fileState{}, err
+	}
+	return f.cacheHash(path, info, sum, hashedAt), nil
+}
+
+// recordHash caches the checksum of data just written to the file
+func (f *FileUtil) recordHash(path string, info fs.FileInfo, data []byte) fileState {
+	sum := sha256.Sum256(data)
+	return f.cacheHash(path, info, sum[:], time.Now())
+}
+
+func (f *FileUtil) cacheHash(path string, info fs.FileInfo, sum []byte, hashedAt time.Time) fileState {
+	state := fileState{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		sha256:  sum,
+		racy:    hashedAt.Sub(info.ModTime()) < racyWindow,
+	}
+	f.hashes[path] = state
+	return state
+}
+
+// MarkerFile marks a directory the client writes product configuration to. It is not part of the configuration.
+const MarkerFile = ".configsaver"
+
+// Dedicated returns true if the root directory only holds product configuration, so files can be deleted from it
+// to match the server. The directory is dedicated if it has the marker file, or is empty, in which case the marker
+// file is created.
+func (f *FileUtil) Dedicated() (bool, error) {
+	marker := filepath.Join(f.RootDir, MarkerFile)
+	if _, err := os.Lstat(marker); err == nil {
+		return true, nil
+	}
+	entries, err := os.ReadDir(f.RootDir)
+	if err != nil {
+		return false, err
+	}
+	if len(entries) > 0 {
+		return false, nil
+	}
+	if err = os.WriteFile(marker, nil, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Manifest returns the SHA-256 checksum of each regular file under the root directory, keyed by the path
+// relative to the root. .git directories and the marker file are skipped. Files that have not changed since they were last hashed
+// are not read again. Manifest must not be called at the same time as ScanFiles.
+func (f *FileUtil) Manifest() (map[string][]byte, error) {
+	manifest := make(map[string][]byte)
+	err := filepath.WalkDir(f.RootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || d.Name() == MarkerFile {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		state, err := f.fileState(path, info)
+		if err != nil {
+			return err
+		}
+		rpath, err := filepath.Rel(f.RootDir, path)
+		if err != nil {
+			return err
+		}
+		manifest[filepath.ToSlash(rpath)] = state.sha256
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error hashing files in %s: %v", f.RootDir, err)
+	}
+	return manifest, nil
+}
+
+// hashFile returns the SHA-256 checksum of the file's contents
+func hashFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,80 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDedicated(t *testing.T) {
+	tests := []struct {
+		name string
+		// files in the directory
+		files []string
+		want  bool
+	}{
+		{name: "empty", want: true},
+		{name: "marker", files: []string{MarkerFile, "am.json"}, want: true},
+		{name: "other files", files: []string{"am.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFileUtil(t.TempDir())
+			for _, name := range tt.files {
+				if err := os.WriteFile(filepath.Join(f.RootDir, name), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := f.Dedicated()
+			if err != nil {
+				t.Fatalf("Dedicated() = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Dedicated() = %v, want %v", got, tt.want)
+			}
+			// a dedicated directory is marked, so it is still dedicated once it has files
+			_, err = os.Stat(filepath.Join(f.RootDir, MarkerFile))
+			if tt.want && err != nil {
+				t.Errorf("marker file: %v", err)
+			}
+			if !tt.want && err == nil {
+				t.Errorf("marker file created in a directory with other files")
+			}
+		})
+	}
+}
+
+func TestManifestSkipsMarker(t *testing.T) {
+	f := NewFileUtil(t.TempDir())
+	if _, err := f.Dedicated(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(f.RootDir, "am.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := f.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest() = %v", err)
+	}
+	if _, ok := manifest["am.json"]; !ok || len(manifest) != 1 {
+		t.Errorf("Manifest() = %v, want only am.json", manifest)
+	}
+}
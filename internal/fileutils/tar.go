@@ -21,29 +21,29 @@ package fileutils
 import (
 	"archive/tar"
 	"bytes"
-	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 )
 
-const (
-	// Whether to gzip the tar file or not.
-	// The gzip compressor triggers tar errors if the number of bytes written is too small
-	// TODO: find out why this is the case (extra padding needed?)
-	// For now, we can use gRPC compression if performance is a concern
-	UseCompression = false
-)
+// ErrInvalidArchive is returned when a tar file can not be read
+var ErrInvalidArchive = errors.New("invalid archive")
 
 type FileUtil struct {
 	RootDir string
-	// map of files from the last scan with the modification time
-	fileStatus map[string]time.Time
+	// map of files from the last scan with their size, modification time and checksum
+	fileStatus map[string]fileState
+	// the latest checksum of each file, so unchanged files are not read again
+	hashes map[string]fileState
+	// the state before the last scan of each file it reported, nil for new files. Used by Requeue.
+	lastScan map[string]*fileState
 	// Which files are no longer in the filesystem
 	DeletedFiles []string
 	// Which files were modified since the last scan
@@ -55,34 +55,38 @@ type FileUtil struct {
 func NewFileUtil(rootDir string) *FileUtil {
 	return &FileUtil{
 		RootDir:    rootDir,
-		fileStatus: make(map[string]time.Time),
+		fileStatus: make(map[string]fileState),
+		hashes:     make(map[string]fileState),
 	}
 }
 
-// Get the entire configuration for the product as a tarball of bytes
-// rootDir is the top of the directory (example, tmp/forgeops).
-// productPath is the relative path under that root where the configuration files are (example, docker/am/product-configs/cdk)
-func (f *FileUtil) GetAllConfiguration(productPath string) ([]byte, error) {
-	var paths []string
+// ReadFiles reads all the files under productPath, the relative path under the root directory.
+// The returned map is keyed by the file path relative to productPath.
+func (f *FileUtil) ReadFiles(productPath string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
 	dir := filepath.Join(f.RootDir, productPath)
-	// recursively walk the directory
 	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
-		if !d.IsDir() {
-			// add to the list of paths we want to include in the tarball
-			paths = append(paths, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
 			return nil
 		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rpath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rpath)] = data
 		return nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error reading files in %s: %v", dir, err)
 	}
-	// create the tarball from the list of paths
-	buf, err := CreateTarBuffer(dir, paths)
-	if err != nil {
-		return nil, err
-	}
-	return buf, nil
+	return files, nil
 }
 
 // Walks the directory tree, creating a list of files added, deleted and modified
@@ -91,6 +95,7 @@ func (f *FileUtil) ScanFiles() error {
 	f.DeletedFiles = make([]string, 0)
 	f.ModifiedFiles = make(map[string]time.Time)
 	f.NewFiles = make(map[string]time.Time)
+	f.lastScan = make(map[string]*fileState)
 
 	currentPaths := make(map[string]time.Time)
 
@@ -99,10 +104,19 @@ func (f *FileUtil) ScanFiles() error {
 		return nil
 	})
 
+	// forget the checksums of files no longer in the filesystem
+	for k := range f.hashes {
+		if _, ok := currentPaths[k]; !ok {
+			delete(f.hashes, k)
+		}
+	}
+
 	// look for files no longer in the filesystem
 	for k, _ := range f.fileStatus {
 		if _, ok := currentPaths[k]; !ok {
 			// remove from the map and add to the list of deleted files
+			previous := f.fileStatus[k]
+			f.lastScan[k] = &previous
 			delete(f.fileStatus, k)
 			// The server wants the relative path, so strip the root directory
 			rpath := k[len(f.RootDir)+1:]
@@ -118,8 +132,21 @@ func (f *FileUtil) ScanFiles() error {
 	return nil
 }
 
+// Requeue puts back the state of the files the last scan reported, so the next scan reports them again. Use it
+// when the changes could not be sent, so they are not lost.
+func (f *FileUtil) Requeue() {
+	for path, previous := range f.lastScan {
+		if previous == nil {
+			delete(f.fileStatus, path)
+		} else {
+			f.fileStatus[path] = *previous
+		}
+	}
+	f.lastScan = make(map[string]*fileState)
+}
+
 // TarUpModifiedFiles creates a tarball of the new and modified files since the last scan
-func (f *FileUtil) TarUpModifiedFiles() ([]byte, error) {
+func (f *FileUtil) TarUpModifiedFiles(compression Compression) ([]byte, error) {
 	allFiles := make([]string, 0)
 	for k, _ := range f.ModifiedFiles {
 		allFiles = append(allFiles, k)
@@ -127,104 +154,302 @@ func (f *FileUtil) TarUpModifiedFiles() ([]byte, error) {
 	for k, _ := range f.NewFiles {
 		allFiles = append(allFiles, k)
 	}
-	return CreateTarBuffer(f.RootDir, allFiles)
+	return CreateTarBuffer(f.RootDir, allFiles, compression)
 }
 
 // Create an in-memory tarball of the listed files.
 // Rootdir is the top of the config directory  (example, tmp/forgeops/docker/am/product-configs/cdk).
 // filepaths are the list of files to include in the tarball
 // The root dir prefix will be stripped from the paths in the tarball
-func CreateTarBuffer(rootDir string, filePaths []string) ([]byte, error) {
-
-	// var buf bytes.Buffer
+func CreateTarBuffer(rootDir string, filePaths []string, compression Compression) ([]byte, error) {
 	var buf bytes.Buffer
+	if err := WriteTar(&buf, rootDir, filePaths, compression); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
 
-	var tarWriter *tar.Writer
-
-	if UseCompression {
-		gzipWriter := gzip.NewWriter(&buf)
-		defer gzipWriter.Close()
+// WriteTar writes a tarball of the listed files to w, in the same way as CreateTarBuffer
+func WriteTar(w io.Writer, rootDir string, filePaths []string, compression Compression) error {
+	return writeTar(w, compression, func(tarWriter *tar.Writer) error {
+		for _, filePath := range filePaths {
+			err := addFileToTarWriter(rootDir, filePath, tarWriter)
+			if err != nil {
+				return fmt.Errorf("could not add file '%s', to tarball, got error '%v'", filePath, err)
+			}
+		}
+		return nil
+	})
+}
 
-		tarWriter = tar.NewWriter(gzipWriter)
-	} else {
-		tarWriter = tar.NewWriter(&buf)
+// Create an in-memory tarball from file contents, for example files read from git.
+// The files map is keyed by the path relative to the product configuration directory.
+func CreateTarBufferFromFiles(files map[string][]byte, compression Compression) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteTarFromFiles(&buf, files, compression); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
+}
 
-	defer tarWriter.Close()
-
-	for _, filePath := range filePaths {
-		err := addFileToTarWriter(rootDir, filePath, tarWriter)
-		if err != nil {
-			return buf.Bytes(), fmt.Errorf("could not add file '%s', to tarball, got error '%v'", filePath, err)
+// WriteTarFromFiles writes a tarball of the file contents to w, in the same way as CreateTarBufferFromFiles
+func WriteTarFromFiles(w io.Writer, files map[string][]byte, compression Compression) error {
+	now := time.Now()
+	return writeTar(w, compression, func(tarWriter *tar.Writer) error {
+		for name, data := range files {
+			header := &tar.Header{
+				Name:    name,
+				Size:    int64(len(data)),
+				Mode:    0644,
+				ModTime: now,
+			}
+			if err := tarWriter.WriteHeader(header); err != nil {
+				return fmt.Errorf("could not write header for file '%s', got error '%v'", name, err)
+			}
+			if _, err := tarWriter.Write(data); err != nil {
+				return fmt.Errorf("could not write file '%s' to the tarball, got error '%v'", name, err)
+			}
 		}
-	}
+		return nil
+	})
+}
 
-	return buf.Bytes(), nil
+// writeTar writes the tarball written by addFiles to w. The tar and compression writers are closed before
+// returning, as closing them writes the end of the archive.
+func writeTar(w io.Writer, compression Compression, addFiles func(*tar.Writer) error) error {
+	compressor, err := compressWriter(w, compression)
+	if err != nil {
+		return err
+	}
+	tarWriter := tar.NewWriter(compressor)
+	if err := addFiles(tarWriter); err != nil {
+		return err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return compressor.Close()
 }
 
-// Given a tar file in a memory buf, unpack it to the specified rootDir directory + optional relative path
+// Given a tar file in a memory buf, unpack it to the specified rootDir directory + optional relative path.
 func (f *FileUtil) UnpackTarBuffer(buf []byte, rpath string) error {
+	return f.UnpackTar(bytes.NewReader(buf), rpath)
+}
+
+// UnpackTar reads a tar file from r, and unpacks it to the specified rootDir directory + optional relative path.
+// The archive is checked before anything is written (see readArchive), and an *UnsafeArchiveError lists any
+// entries that could write outside the directory.
+func (f *FileUtil) UnpackTar(r io.Reader, rpath string) error {
 
 	targetDir := filepath.Join(f.RootDir, rpath)
 
 	log.Printf("Unpacking tar file to %s\n", targetDir)
-	reader := bytes.NewReader(buf)
-	var tarReader *tar.Reader
-
-	if UseCompression {
-		gzipReader, err := gzip.NewReader(reader)
-		if err != nil {
-			return fmt.Errorf("could not create gzip reader, got error '%v'", err.Error())
-		}
-		defer gzipReader.Close()
-		tarReader = tar.NewReader(gzipReader)
-
-	} else {
-		tarReader = tar.NewReader(reader)
+	a, err := readArchive(r)
+	if err != nil {
+		return err
 	}
 
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
 		return fmt.Errorf("could not create directory '%s', got error '%v'", f.RootDir, err.Error())
 	}
+	realDir, err := f.realDir(rpath)
+	if err != nil {
+		return err
+	}
 
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
+	for name, data := range a.files {
+		path, err := prepareEntry(realDir, name)
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("could not create file '%s', got error '%v'", path, err.Error())
 		}
+	}
+	links := make([]string, 0, len(a.symlinks))
+	defer func() {
+		// links that lead outside the directory are not left behind
 		if err != nil {
-			return fmt.Errorf("could not read next tar header, got error '%v'", err.Error())
+			for _, path := range links {
+				os.Remove(path)
+			}
+		}
+	}()
+	for name, target := range a.symlinks {
+		var path string
+		if path, err = prepareEntry(realDir, name); err != nil {
+			return err
+		}
+		if err = os.Symlink(target, path); err != nil {
+			return fmt.Errorf("could not create link '%s', got error '%v'", path, err.Error())
 		}
+		links = append(links, path)
+	}
+	// A link's target was checked where it is in the archive. Now the links exist, check where they really lead.
+	for name := range a.symlinks {
+		if err = checkInside(realDir, name, filepath.Join(realDir, filepath.FromSlash(name))); err != nil {
+			return err
+		}
+	}
 
-		path := filepath.Join(targetDir, header.Name)
-		fmt.Println(path)
+	return nil
+}
 
-		err = os.MkdirAll(filepath.Dir(path), 0755)
-		if err != nil {
-			return fmt.Errorf("could not create directory for file '%s', got error '%v'", path, err.Error())
+// prepareEntry creates the parent directory of an archive entry, and removes an existing link at its path so
+// the entry is not written through it. Links already in the directory, or created by earlier entries, must not
+// lead outside of the directory.
+func prepareEntry(dir, name string) (string, error) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	parent := filepath.Dir(path)
+	if err := checkInside(dir, name, parent); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", fmt.Errorf("could not create directory for file '%s', got error '%v'", path, err.Error())
+	}
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(path); err != nil {
+			return "", fmt.Errorf("could not replace link '%s', got error '%v'", path, err.Error())
 		}
+	}
+	return path, nil
+}
+
+// Most symbolic links followed to find the file a path refers to
+const maxLinkDepth = 40
 
-		file, err := os.Create(path)
+// checkInside returns an *UnsafeArchiveError for the entry name if the path p, once links are followed, is not
+// under the directory dir. dir must be a real path, with no links.
+func checkInside(dir, name, p string) error {
+	real, err := realPath(p)
+	if err != nil {
+		return fmt.Errorf("could not resolve '%s', got error '%v'", p, err.Error())
+	}
+	if real != dir && !strings.HasPrefix(real, dir+string(filepath.Separator)) {
+		return &UnsafeArchiveError{Entries: map[string]string{name: "path is outside the directory through a link"}}
+	}
+	return nil
+}
+
+// realPath returns the absolute path p refers to once links are followed. Unlike filepath.EvalSymlinks the path,
+// or the target of a link in it, does not have to exist, as it may be created by writing to p.
+func realPath(p string) (string, error) {
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	sep := string(filepath.Separator)
+	resolved := sep
+	parts := strings.Split(p, sep)
+	links := 0
+	for len(parts) > 0 {
+		part := parts[0]
+		parts = parts[1:]
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+		next := filepath.Join(resolved, part)
+		info, err := os.Lstat(next)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+		if links++; links > maxLinkDepth {
+			return "", fmt.Errorf("too many links in %s", p)
+		}
+		target, err := os.Readlink(next)
 		if err != nil {
-			return fmt.Errorf("could not create file '%s', got error '%v'", path, err.Error())
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			resolved = sep
 		}
-		defer file.Close()
+		// the target replaces the link, and is resolved relative to the directory holding it
+		parts = append(strings.Split(target, sep), parts...)
+	}
+	return resolved, nil
+}
 
-		_, err = io.Copy(file, tarReader)
-		if err != nil {
-			return fmt.Errorf("could not copy data from tarball to file '%s', got error '%v'", header.Name, err.Error())
+// realDir returns the real path of the directory under the root directory, which may not exist yet
+func (f *FileUtil) realDir(prefix string) (string, error) {
+	dir, err := realPath(filepath.Join(f.RootDir, prefix))
+	if err != nil {
+		return "", fmt.Errorf("could not resolve directory '%s', got error '%v'", filepath.Join(f.RootDir, prefix), err.Error())
+	}
+	return dir, nil
+}
+
+// ReadTarBuffer reads the files in a tar file in a memory buf, without writing them to the filesystem.
+// The returned map is keyed by the file path relative to the root of the archive (example, realm/foo.json).
+func ReadTarBuffer(buf []byte) (map[string][]byte, error) {
+	return ReadTar(bytes.NewReader(buf))
+}
+
+// ReadTar reads the files in a tar file from r, in the same way as ReadTarBuffer. The archive is checked in the
+// same way as UnpackTar. Links are read as a copy of the file they point to.
+func ReadTar(r io.Reader) (map[string][]byte, error) {
+	a, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	files := a.files
+	for name, target := range a.symlinks {
+		data, ok := files[path.Join(path.Dir(name), target)]
+		if !ok {
+			log.Printf("skipping link %s: %s is not a file in the archive", name, target)
+			continue
 		}
+		files[name] = data
 	}
+	return files, nil
+}
 
+// WriteFiles writes files to the filesystem. The prefix is a subpath of the root directory, and the files
+// map is keyed by the path under that directory. Missing directories are created. A file written through a link
+// already in the directory must stay under it, or an *UnsafeArchiveError is returned.
+func (f *FileUtil) WriteFiles(files map[string][]byte, prefix string) error {
+	dir, err := f.realDir(prefix)
+	if err != nil {
+		return err
+	}
+	for name, data := range files {
+		path := filepath.Join(f.RootDir, prefix, name)
+		if err := checkInside(dir, name, path); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("could not create directory for file '%s', got error '%v'", path, err.Error())
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("could not write file '%s', got error '%v'", path, err.Error())
+		}
+	}
 	return nil
 }
 
 // DeleteFiles deletes a list of files from the filesystem. The prefix is a subpath of the root directory
 // for example if the root is /tmp/forgeops, the prefix is docker/am/product-configs/cdk, the file[*] path is a file under that directory.
+// A file under a link already in the directory must stay under it, or an *UnsafeArchiveError is returned.
 func (f *FileUtil) DeleteFiles(files []string, prefix string) error {
+	dir, err := f.realDir(prefix)
+	if err != nil {
+		return err
+	}
 	for _, file := range files {
 		path := filepath.Join(f.RootDir, prefix, file)
 		fmt.Printf("Deleting %s\n", path)
+		// a link itself is removed, not the file it points to, so only its directory is checked
+		if err := checkInside(dir, file, filepath.Dir(path)); err != nil {
+			return err
+		}
 		err := os.RemoveAll(path)
 		if err != nil {
 			fmt.Printf("Error deleting %s, %v\n", path, err)
@@ -234,6 +459,58 @@ func (f *FileUtil) DeleteFiles(files []string, prefix string) error {
 	return nil
 }
 
+// ApplyChanges writes files changed elsewhere (for example, on the server) under the root directory, and deletes
+// removed files. The scan baseline is updated so the next ScanFiles does not report them as local changes.
+// Files changed locally since the last scan are left alone, so local edits are not lost; they are returned.
+func (f *FileUtil) ApplyChanges(files map[string][]byte, deleted []string) ([]string, error) {
+	skipped := make([]string, 0)
+	for name, data := range files {
+		path := filepath.Join(f.RootDir, name)
+		if f.changedLocally(path) {
+			skipped = append(skipped, name)
+			continue
+		}
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+			continue
+		}
+		if err := f.WriteFiles(map[string][]byte{name: data}, ""); err != nil {
+			return skipped, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return skipped, err
+		}
+		f.fileStatus[path] = f.recordHash(path, info, data)
+	}
+	for _, name := range deleted {
+		path := filepath.Join(f.RootDir, name)
+		if f.changedLocally(path) {
+			skipped = append(skipped, name)
+			continue
+		}
+		if err := f.DeleteFiles([]string{name}, ""); err != nil {
+			return skipped, err
+		}
+		delete(f.fileStatus, path)
+		delete(f.hashes, path)
+	}
+	return skipped, nil
+}
+
+// changedLocally returns true if the file was created, or its contents changed, since the last scan
+func (f *FileUtil) changedLocally(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	last, ok := f.fileStatus[path]
+	if !ok {
+		return true
+	}
+	current, err := f.fileState(path, info)
+	return err != nil || !bytes.Equal(current.sha256, last.sha256)
+}
+
 // Add a file to the tarball. The rootDir prefix is stripped from the archive so that
 // the receiver can restore the archive to a preferred relative location
 func addFileToTarWriter(rootDir, filePath string, tarWriter *tar.Writer) error {
@@ -248,10 +525,13 @@ func addFileToTarWriter(rootDir, filePath string, tarWriter *tar.Writer) error {
 		return fmt.Errorf("could not get stat for file '%s', got error '%v'", filePath, err.Error())
 	}
 
-	tarPath := filePath[len(rootDir):]
+	tarPath, err := filepath.Rel(rootDir, filePath)
+	if err != nil {
+		return fmt.Errorf("could not get the path of file '%s' in the tarball, got error '%v'", filePath, err.Error())
+	}
 
 	header := &tar.Header{
-		Name:    tarPath,
+		Name:    filepath.ToSlash(tarPath),
 		Size:    stat.Size(),
 		Mode:    int64(stat.Mode()),
 		ModTime: stat.ModTime(),
@@ -274,26 +554,44 @@ func addFileToTarWriter(rootDir, filePath string, tarWriter *tar.Writer) error {
 // Function called for every file and directory we visit
 // Map recentPass are the new files in this scan iteration - we use this to determine if there are files in
 // map f.FileStatus that are not in the current iteration. These are files that have been deleted from the filesystem
-// Any new paths found are also added to the map f.FileStatus.
+// Any new paths found are also added to the map f.FileStatus. A file is only modified if its contents changed;
+// applications often rewrite files without changing them.
 func (f *FileUtil) walkDirFunction(path string, d fs.DirEntry, recentPass map[string]time.Time) error {
-	// ignore .git/* and any path that is a directory
-	if !d.IsDir() && !strings.Contains(d.Name(), ".git") {
-		info, _ := d.Info()
-		t := info.ModTime()
-		// fmt.Printf("file: %s %v\n", path, t)
+	// ignore .git/*, the marker file and any path that is a directory
+	if !d.IsDir() && !strings.Contains(d.Name(), ".git") && d.Name() != MarkerFile {
+		// follow links, so a change to the file a link points to is seen
+		info, err := os.Stat(path)
+		var state fileState
+		if err == nil {
+			state, err = f.fileState(path, info)
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			// deleted during the scan
+			return nil
+		}
+		if err != nil {
+			// keep the file as it was, rather than report it deleted
+			log.Printf("could not read %s: %v", path, err)
+			if val, ok := f.fileStatus[path]; ok {
+				recentPass[path] = val.modTime
+			}
+			return err
+		}
+		t := state.modTime
 		// Look up value in the main current map
 		if val, ok := f.fileStatus[path]; ok {
-			// file exists, but the mod time has changed.
-			if t != val {
+			// file exists, but the contents have changed.
+			if !bytes.Equal(state.sha256, val.sha256) {
 				fmt.Printf("%s changed time %v\n", path, t)
-				f.fileStatus[path] = t
 				f.ModifiedFiles[path] = t
+				f.lastScan[path] = &val
 			}
 		} else { // file is not in currentFileStatus map
 			fmt.Printf("adding %s\n", path)
-			f.fileStatus[path] = t
 			f.NewFiles[path] = t
+			f.lastScan[path] = nil
 		}
+		f.fileStatus[path] = state
 		// record for next pass
 		recentPass[path] = t
 	}
@@ -0,0 +1,108 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFile writes the file with the modification time
+func writeFile(t *testing.T, path, data string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func scan(t *testing.T, f *FileUtil) {
+	t.Helper()
+	if err := f.ScanFiles(); err != nil {
+		t.Fatalf("ScanFiles() = %v", err)
+	}
+}
+
+func TestScanRewriteSameContent(t *testing.T) {
+	f := NewFileUtil(t.TempDir())
+	path := filepath.Join(f.RootDir, "am.json")
+	writeFile(t, path, "a", time.Now().Add(-time.Hour))
+	scan(t, f)
+
+	// the application writes the file again without changing it
+	writeFile(t, path, "a", time.Now())
+	scan(t, f)
+	if len(f.ModifiedFiles) > 0 || len(f.NewFiles) > 0 {
+		t.Errorf("ScanFiles() modified %v, new %v, want a rewrite with the same contents not reported", f.ModifiedFiles, f.NewFiles)
+	}
+}
+
+func TestScanEditWithinSameSecond(t *testing.T) {
+	f := NewFileUtil(t.TempDir())
+	path := filepath.Join(f.RootDir, "am.json")
+	modTime := time.Now().Truncate(time.Second)
+	writeFile(t, path, "aaaa", modTime)
+	scan(t, f)
+
+	// an edit of the same size, with a modification time that does not change on a filesystem with whole seconds
+	writeFile(t, path, "bbbb", modTime)
+	scan(t, f)
+	if _, ok := f.ModifiedFiles[path]; !ok {
+		t.Errorf("ScanFiles() modified %v, want %s reported", f.ModifiedFiles, path)
+	}
+}
+
+func TestRequeue(t *testing.T) {
+	f := NewFileUtil(t.TempDir())
+	added := filepath.Join(f.RootDir, "added.json")
+	changed := filepath.Join(f.RootDir, "changed.json")
+	deleted := filepath.Join(f.RootDir, "deleted.json")
+	writeFile(t, changed, "a", time.Now())
+	writeFile(t, deleted, "a", time.Now())
+	scan(t, f)
+
+	writeFile(t, added, "a", time.Now())
+	writeFile(t, changed, "b", time.Now())
+	if err := os.Remove(deleted); err != nil {
+		t.Fatal(err)
+	}
+	scan(t, f)
+	// the changes could not be sent, so the next scan reports them again
+	f.Requeue()
+	scan(t, f)
+	if _, ok := f.NewFiles[added]; !ok || len(f.NewFiles) != 1 {
+		t.Errorf("ScanFiles() new %v, want %s", f.NewFiles, added)
+	}
+	if _, ok := f.ModifiedFiles[changed]; !ok || len(f.ModifiedFiles) != 1 {
+		t.Errorf("ScanFiles() modified %v, want %s", f.ModifiedFiles, changed)
+	}
+	if len(f.DeletedFiles) != 1 || f.DeletedFiles[0] != "deleted.json" {
+		t.Errorf("ScanFiles() deleted %v, want deleted.json", f.DeletedFiles)
+	}
+
+	// once sent, they are not reported again
+	scan(t, f)
+	if len(f.NewFiles) > 0 || len(f.ModifiedFiles) > 0 || len(f.DeletedFiles) > 0 {
+		t.Errorf("ScanFiles() new %v, modified %v, deleted %v, want no changes", f.NewFiles, f.ModifiedFiles, f.DeletedFiles)
+	}
+}
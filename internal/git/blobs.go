@@ -0,0 +1,67 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package git
+
+import (
+	"fmt"
+
+	g "github.com/libgit2/git2go/v31"
+)
+
+// TreeBlobs returns the blob id of each file under dir at the revision, keyed by the path relative to dir,
+// and the commit id. The files are not read.
+func (gitRepo *GitRepo) TreeBlobs(rev, dir string) (string, map[string]string, error) {
+	commit, err := gitRepo.resolveCommit(rev)
+	if err != nil {
+		return "", nil, err
+	}
+	defer commit.Free()
+
+	tree, err := gitRepo.subTree(commit, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tree.Free()
+
+	blobs := make(map[string]string)
+	err = tree.Walk(func(root string, entry *g.TreeEntry) int {
+		if entry.Type == g.ObjectBlob {
+			blobs[root+entry.Name] = entry.Id.String()
+		}
+		return 0
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("could not read tree %s at %s: %v", dir, rev, err)
+	}
+	return commit.Id().String(), blobs, nil
+}
+
+// ReadBlob returns the contents of the blob with the id
+func (gitRepo *GitRepo) ReadBlob(id string) ([]byte, error) {
+	oid, err := g.NewOid(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob id %s: %v", id, err)
+	}
+	blob, err := gitRepo.repo.LookupBlob(oid)
+	if err != nil {
+		return nil, &GitError{Op: "read blob", Name: id, Err: err}
+	}
+	defer blob.Free()
+	return blob.Contents(), nil
+}
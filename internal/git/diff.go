@@ -0,0 +1,277 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	g "github.com/libgit2/git2go/v31"
+)
+
+// Change is how a file changed between two revisions
+type Change int
+
+const (
+	Modified Change = iota
+	Added
+	Deleted
+)
+
+// FileDiff is the change to a single file
+type FileDiff struct {
+	// Path relative to the directory that was compared
+	Path   string
+	Change Change
+	// unified diff of the change
+	Patch string
+}
+
+// DiffRevisions compares the files under dir between two revisions (commit, branch or tag).
+// Returns the differences, and the commit ids the revisions resolved to.
+func (gitRepo *GitRepo) DiffRevisions(from, to, dir string) ([]FileDiff, string, string, error) {
+	fromCommit, err := gitRepo.resolveCommit(from)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer fromCommit.Free()
+	toCommit, err := gitRepo.resolveCommit(to)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer toCommit.Free()
+
+	fromTree, err := fromCommit.Tree()
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer fromTree.Free()
+	toTree, err := toCommit.Tree()
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer toTree.Free()
+
+	diffs, err := gitRepo.diffTrees(fromTree, toTree, dir)
+	return diffs, fromCommit.Id().String(), toCommit.Id().String(), err
+}
+
+// DiffFiles compares the files under dir at revision from with a set of changes that have not been committed.
+// files are new or updated file contents and deleted are removed files, both relative to dir.
+// Nothing is written to the working tree, the index or the object database. Returns the differences, and the
+// commit id from resolved to.
+func (gitRepo *GitRepo) DiffFiles(from, dir string, files map[string][]byte, deleted []string) ([]FileDiff, string, error) {
+	scratch, err := gitRepo.scratchRepo()
+	if err != nil {
+		return nil, "", err
+	}
+	defer scratch.repo.Free()
+	return scratch.diffFiles(from, dir, files, deleted)
+}
+
+// scratchRepo opens the repository again, with an in memory object database backend that new objects are written
+// to. They are dropped when the returned repository is freed.
+func (gitRepo *GitRepo) scratchRepo() (*GitRepo, error) {
+	repo, err := g.OpenRepository(gitRepo.repo.Path())
+	if err != nil {
+		return nil, err
+	}
+	odb, err := repo.Odb()
+	if err != nil {
+		repo.Free()
+		return nil, err
+	}
+	defer odb.Free()
+	if _, err = g.NewMempack(odb); err != nil {
+		repo.Free()
+		return nil, err
+	}
+	return &GitRepo{LocalPath: gitRepo.LocalPath, Branch: gitRepo.Branch, repo: repo}, nil
+}
+
+func (gitRepo *GitRepo) diffFiles(from, dir string, files map[string][]byte, deleted []string) ([]FileDiff, string, error) {
+	fromCommit, err := gitRepo.resolveCommit(from)
+	if err != nil {
+		return nil, "", err
+	}
+	defer fromCommit.Free()
+	fromTree, err := fromCommit.Tree()
+	if err != nil {
+		return nil, "", err
+	}
+	defer fromTree.Free()
+
+	toTree, err := gitRepo.treeWithChanges(fromTree, dir, files, deleted)
+	if err != nil {
+		return nil, "", err
+	}
+	defer toTree.Free()
+
+	diffs, err := gitRepo.diffTrees(fromTree, toTree, dir)
+	return diffs, fromCommit.Id().String(), err
+}
+
+// treeWithChanges creates a new tree from base with the changes applied, using an in memory index.
+// The new blobs and trees are written to the object database, so use a scratch repository.
+func (gitRepo *GitRepo) treeWithChanges(base *g.Tree, dir string, files map[string][]byte, deleted []string) (*g.Tree, error) {
+	index, err := g.NewIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer index.Free()
+	if err = index.ReadTree(base); err != nil {
+		return nil, err
+	}
+
+	for name, data := range files {
+		blobId, err := gitRepo.repo.CreateBlobFromBuffer(data)
+		if err != nil {
+			return nil, fmt.Errorf("could not create blob for %s: %v", name, err)
+		}
+		p, mode, err := gitRepo.writtenPath(index, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		entry := &g.IndexEntry{
+			Path: p,
+			Id:   blobId,
+			Mode: mode,
+			Size: uint32(len(data)),
+		}
+		if err = index.Add(entry); err != nil {
+			return nil, fmt.Errorf("could not add %s to the index: %v", name, err)
+		}
+	}
+	for _, name := range deleted {
+		// the deleted path may be a file or a directory. Each file under a directory is removed.
+		for _, p := range indexPaths(index, path.Join(dir, name)) {
+			if err = index.RemoveByPath(p); err != nil {
+				return nil, fmt.Errorf("could not remove %s from the index: %v", p, err)
+			}
+		}
+	}
+
+	treeId, err := index.WriteTreeTo(gitRepo.repo)
+	if err != nil {
+		return nil, err
+	}
+	return gitRepo.repo.LookupTree(treeId)
+}
+
+// Most symbolic links followed to find the file a path refers to
+const maxLinkDepth = 8
+
+// writtenPath returns the path and mode of the file that writing to p changes. Writing to a symbolic link writes
+// the file it points to, so links are followed. An existing file keeps its mode, such as the executable bit.
+func (gitRepo *GitRepo) writtenPath(index *g.Index, p string) (string, g.Filemode, error) {
+	for i := 0; i < maxLinkDepth; i++ {
+		entry, err := index.EntryByPath(p, 0)
+		if g.IsErrorCode(err, g.ErrorCodeNotFound) {
+			return p, g.FilemodeBlob, nil
+		}
+		if err != nil {
+			return "", 0, fmt.Errorf("could not read %s from the index: %v", p, err)
+		}
+		if entry.Mode != g.FilemodeLink {
+			return p, entry.Mode, nil
+		}
+		blob, err := gitRepo.repo.LookupBlob(entry.Id)
+		if err != nil {
+			return "", 0, fmt.Errorf("could not read link %s: %v", p, err)
+		}
+		target := string(blob.Contents())
+		blob.Free()
+		if path.IsAbs(target) {
+			return "", 0, fmt.Errorf("%w: %s links outside the repository", ErrConflict, p)
+		}
+		p = path.Join(path.Dir(p), target)
+		if p == ".." || strings.HasPrefix(p, "../") {
+			return "", 0, fmt.Errorf("%w: %s links outside the repository", ErrConflict, entry.Path)
+		}
+	}
+	return "", 0, fmt.Errorf("%w: too many links to %s", ErrConflict, p)
+}
+
+// indexPaths returns the index entries for the file p, or for the files under the directory p
+func indexPaths(index *g.Index, p string) []string {
+	var paths []string
+	count := index.EntryCount()
+	for i := uint(0); i < count; i++ {
+		entry, err := index.EntryByIndex(i)
+		if err != nil {
+			continue
+		}
+		if entry.Path == p || strings.HasPrefix(entry.Path, p+"/") {
+			paths = append(paths, entry.Path)
+		}
+	}
+	return paths
+}
+
+// diffTrees returns the per file differences under dir between two trees
+func (gitRepo *GitRepo) diffTrees(oldTree, newTree *g.Tree, dir string) ([]FileDiff, error) {
+	opts, err := g.DefaultDiffOptions()
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		opts.Pathspec = []string{dir}
+	}
+	diff, err := gitRepo.repo.DiffTreeToTree(oldTree, newTree, &opts)
+	if err != nil {
+		return nil, err
+	}
+	defer diff.Free()
+
+	count, err := diff.NumDeltas()
+	if err != nil {
+		return nil, err
+	}
+	diffs := make([]FileDiff, 0, count)
+	for i := 0; i < count; i++ {
+		delta, err := diff.Delta(i)
+		if err != nil {
+			return nil, err
+		}
+		patch, err := diff.Patch(i)
+		if err != nil {
+			return nil, err
+		}
+		text, err := patch.String()
+		patch.Free()
+		if err != nil {
+			return nil, err
+		}
+
+		change := Modified
+		switch delta.Status {
+		case g.DeltaAdded:
+			change = Added
+		case g.DeltaDeleted:
+			change = Deleted
+		}
+		diffs = append(diffs, FileDiff{
+			Path:   relativePath(dir, delta.NewFile.Path),
+			Change: change,
+			Patch:  text,
+		})
+	}
+	return diffs, nil
+}
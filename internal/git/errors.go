@@ -0,0 +1,98 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package git
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	g "github.com/libgit2/git2go/v31"
+)
+
+// ErrLocked is returned when the repository is locked by another git process
+var ErrLocked = errors.New("repository is locked")
+
+// How old an index.lock file must be before it is treated as left behind by a git process that died.
+// The server serializes its own use of the repo, so other processes only hold the lock briefly.
+const staleLockAge = 30 * time.Second
+
+// GitError is returned when a git operation fails
+type GitError struct {
+	// The operation, for example add or commit
+	Op string
+	// The file, branch or remote the operation was working on, if any
+	Name string
+	Err  error
+}
+
+func (e *GitError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("git %s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("git %s %s: %v", e.Op, e.Name, e.Err)
+}
+
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
+
+// retryIfLocked runs an operation that writes the index. If the index is locked by a stale index.lock file,
+// the lock is removed and the operation is tried again. A lock that is not stale is returned as ErrLocked.
+func (gitRepo *GitRepo) retryIfLocked(op func() error) error {
+	err := op()
+	if !g.IsErrorCode(err, g.ErrorCodeLocked) {
+		return err
+	}
+	if gitRepo.removeStaleIndexLock() {
+		err = op()
+	}
+	if g.IsErrorCode(err, g.ErrorCodeLocked) {
+		return fmt.Errorf("%w: %v", ErrLocked, err)
+	}
+	return err
+}
+
+// removeStaleIndexLock removes the index.lock file if it is stale. Returns true if the operation
+// that found the index locked should be tried again.
+func (gitRepo *GitRepo) removeStaleIndexLock() bool {
+	lock := filepath.Join(gitRepo.repo.Path(), "index.lock")
+	info, err := os.Stat(lock)
+	if os.IsNotExist(err) {
+		// released since
+		return true
+	}
+	if err != nil {
+		log.Printf("could not check lock %s: %v", lock, err)
+		return false
+	}
+	if age := time.Since(info.ModTime()); age < staleLockAge {
+		log.Printf("%s is held by another process (%v old)", lock, age.Round(time.Second))
+		return false
+	}
+	log.Printf("removing stale lock %s, last modified %v", lock, info.ModTime())
+	if err = os.Remove(lock); err != nil && !os.IsNotExist(err) {
+		log.Printf("could not remove stale lock %s: %v", lock, err)
+		return false
+	}
+	return true
+}
@@ -24,6 +24,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	g "github.com/libgit2/git2go/v31"
 )
@@ -32,15 +35,47 @@ const (
 	defaultGitRepo = "https://stash.forgerock.org/scm/cloud/forgeops.git"
 )
 
+var (
+	// ErrRevisionNotFound is returned when a commit, branch or tag can not be found
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrPathNotFound is returned when a path does not exist in a revision
+	ErrPathNotFound = errors.New("path not found")
+	// ErrConflict is returned when changes conflict with the state of the repository
+	ErrConflict = errors.New("conflict")
+	// ErrPushRejected is returned when the remote rejects a push, for example a non fast forward update
+	ErrPushRejected = errors.New("push rejected by remote")
+)
+
+// Author is the person or process that made the changes in a commit
+type Author struct {
+	Name  string
+	Email string
+}
+
+// Valid returns true if the author can sign a commit: git needs a name and an email, and neither may contain
+// the < and > that delimit the email, or a line break.
+func (a Author) Valid() bool {
+	return a.Name != "" && a.Email != "" && !strings.ContainsAny(a.Name+a.Email, "<>\n")
+}
+
+// The server commits changes, and is the author when no other author is given
+var serverAuthor = Author{Name: "config-saver", Email: "config-saver@forgerock.com"}
+
 type GitRepo struct {
 	repo      *g.Repository
 	LocalPath string
 	RemoteUrl string
+	// The local branch we commit to. It tracks origin/Branch
+	Branch string
+	// the last commit that changed each directory, see LastChange
+	historyLock sync.Mutex
+	lastChanges map[string]lastChange
 }
 
 // OpenGitRepo opens a git repository at localPath and switches to the branch. If the local repo
-// does not exist the repo will be cloned from the remoteUrl.
-func OpenGitRepo(localPath, branch string) (*GitRepo, error) {
+// does not exist the repo will be cloned from the remoteUrl. If the branch does not exist
+// upstream it is created from baseRef, which can be a branch or a tag.
+func OpenGitRepo(localPath, branch, baseRef string) (*GitRepo, error) {
 
 	var repo *g.Repository
 	var err error
@@ -54,35 +89,46 @@ func OpenGitRepo(localPath, branch string) (*GitRepo, error) {
 	repo, err = g.OpenRepository(localPath)
 	if err != nil {
 		log.Printf("%s not found, attempting to clone %s", localPath, remoteUrl)
-		cloneOptions := &g.CloneOptions{}
-		sshPath := os.Getenv("GIT_SSH_PATH")
-		if sshPath != "" {
-			fmt.Printf("Configuring ssh credentials\n")
-			if _, err := os.Stat(sshPath); err != nil {
-				log.Fatalf("GIT_SSH_PATH path %s does not exist or is not readable", sshPath)
-			}
-			cloneOptions = &g.CloneOptions{
-				FetchOptions: &g.FetchOptions{
-					RemoteCallbacks: g.RemoteCallbacks{
-						CredentialsCallback:      credentialsCallback,
-						CertificateCheckCallback: certificateCheckCallback,
-					},
-				},
-			}
+		callbacks, err := remoteCallbacks()
+		if err != nil {
+			return nil, &GitError{Op: "clone", Name: remoteUrl, Err: err}
+		}
+		cloneOptions := &g.CloneOptions{
+			FetchOptions: &g.FetchOptions{
+				RemoteCallbacks: callbacks,
+			},
 		}
 		repo, err = g.Clone(remoteUrl, localPath, cloneOptions)
 		if err != nil {
-			log.Fatal(err)
+			return nil, &GitError{Op: "clone", Name: remoteUrl, Err: err}
 		}
 	}
 
 	// This will refresh the working tree with the current branch
 	// This is probably what we want most of the time. Files deleted in the working directory
 	// get restored
-	if err = checkoutBranch(repo, branch); err != nil {
-		log.Fatalf("Failed to checkout branch %s: %v", branch, err)
+	if err = checkoutBranch(repo, branch, baseRef); err != nil {
+		repo.Free()
+		return nil, &GitError{Op: "checkout", Name: branch, Err: err}
+	}
+	return &GitRepo{repo: repo, LocalPath: localPath, RemoteUrl: remoteUrl, Branch: branch}, nil
+}
+
+// remoteCallbacks returns the callbacks used for clone, fetch and push. If GIT_SSH_PATH is set
+// the ssh keys in that directory are used to authenticate to the remote.
+func remoteCallbacks() (g.RemoteCallbacks, error) {
+	sshPath := os.Getenv("GIT_SSH_PATH")
+	if sshPath == "" {
+		return g.RemoteCallbacks{}, nil
 	}
-	return &GitRepo{repo, localPath, remoteUrl}, nil
+	fmt.Printf("Configuring ssh credentials\n")
+	if _, err := os.Stat(sshPath); err != nil {
+		return g.RemoteCallbacks{}, fmt.Errorf("GIT_SSH_PATH path %s does not exist or is not readable: %v", sshPath, err)
+	}
+	return g.RemoteCallbacks{
+		CredentialsCallback:      credentialsCallback,
+		CertificateCheckCallback: certificateCheckCallback,
+	}, nil
 }
 
 func credentialsCallback(urlstring, username string, allowedTypes g.CredType) (*g.Cred, error) {
@@ -102,49 +148,91 @@ func certificateCheckCallback(cert *g.Certificate, valid bool, hostname string)
 // https://stackoverflow.com/questions/31496175/git2go-simulate-git-checkout-and-an-immediate-git-push?rq=1
 // https://blog.gopheracademy.com/advent-2014/git2go-tutorial/
 
-// get the git status of the repo, commit any changed files.
-func (gitRepo *GitRepo) GitStatusAndCommit() error {
+// get the git status of the repo, commit any changed files with the message. If paths are given only
+// changes under those paths are staged, so changes being made elsewhere in the working tree are not committed.
+// The author may be nil. Returns the commit id of HEAD after the commit.
+func (gitRepo *GitRepo) GitStatusAndCommit(message string, author *Author, paths ...string) (string, error) {
 
 	opts := &g.StatusOptions{
-		Flags: (g.StatusOptIncludeUntracked),
+		Flags:    (g.StatusOptIncludeUntracked),
+		Pathspec: paths,
 	}
 	list, err := gitRepo.repo.StatusList(opts)
-
 	if err != nil {
-		log.Printf("Failed to get status: %v", err)
-		return err
+		return "", &GitError{Op: "status", Err: err}
 	}
+	defer list.Free()
 
-	count, _ := list.EntryCount()
+	count, err := list.EntryCount()
+	if err != nil {
+		return "", &GitError{Op: "status", Err: err}
+	}
 	log.Printf("Processing %d git changes\n", count)
 
 	for i := 0; i < count; i++ {
-		entry, _ := list.ByIndex(i)
-		fmt.Printf("%+v\nstatus=0x%x\n\n", entry, entry.Status)
-		// file is newly added
-		if entry.Status == g.StatusWtNew {
-			s := entry.IndexToWorkdir.NewFile.Path
-			gitRepo.addToIndex(s)
-
-		}
-		// file is modified
-		if entry.Status == g.StatusWtModified {
-			s := entry.IndexToWorkdir.NewFile.Path
-			gitRepo.addToIndex(s)
-
+		entry, err := list.ByIndex(i)
+		if err != nil {
+			return "", &GitError{Op: "status", Err: err}
 		}
-		// fie us deleted
-		if entry.Status == g.StatusWtDeleted {
+		fmt.Printf("%+v\nstatus=0x%x\n\n", entry, entry.Status)
+		// the status is a set of flags, so a file can be both staged and changed again in the working tree
+		switch {
+		// file is deleted
+		case entry.Status&g.StatusWtDeleted != 0:
 			s := entry.IndexToWorkdir.NewFile.Path
 			fmt.Printf("File removed %s\n", s)
-			gitRepo.removeFromIndex(s)
+			err = gitRepo.removeFromIndex(s)
+		// file is newly added, modified, or changed type, for example from a file to a link
+		case entry.Status&(g.StatusWtNew|g.StatusWtModified|g.StatusWtTypeChange|g.StatusWtRenamed) != 0:
+			err = gitRepo.addToIndex(entry.IndexToWorkdir.NewFile.Path)
+		}
+		if err != nil {
+			return "", err
 		}
 	}
 	if count > 0 {
-		gitRepo.Commit("automated commit")
+		return gitRepo.commitPaths(message, author, paths)
 	}
 
-	return nil
+	return gitRepo.HeadCommitId()
+}
+
+// Changes returns the files under dir that were added, modified or deleted since HEAD, in the index or the
+// working tree. These are the changes GitStatusAndCommit would commit. Paths are relative to the root of the repo.
+func (gitRepo *GitRepo) Changes(dir string) (added, modified, deleted []string, err error) {
+	opts := &g.StatusOptions{
+		Flags:    g.StatusOptIncludeUntracked | g.StatusOptRecurseUntrackedDirs,
+		Pathspec: []string{dir},
+	}
+	list, err := gitRepo.repo.StatusList(opts)
+	if err != nil {
+		return nil, nil, nil, &GitError{Op: "status", Name: dir, Err: err}
+	}
+	defer list.Free()
+
+	count, err := list.EntryCount()
+	if err != nil {
+		return nil, nil, nil, &GitError{Op: "status", Name: dir, Err: err}
+	}
+	for i := 0; i < count; i++ {
+		entry, err := list.ByIndex(i)
+		if err != nil {
+			return nil, nil, nil, &GitError{Op: "status", Name: dir, Err: err}
+		}
+		path := entry.IndexToWorkdir.NewFile.Path
+		if path == "" {
+			path = entry.HeadToIndex.NewFile.Path
+		}
+		switch {
+		case entry.Status&(g.StatusWtNew|g.StatusIndexNew) != 0 && entry.Status&g.StatusWtDeleted == 0:
+			added = append(added, path)
+		case entry.Status&(g.StatusWtDeleted|g.StatusIndexDeleted) != 0 && entry.Status&g.StatusIndexNew == 0:
+			deleted = append(deleted, path)
+		case entry.Status != g.StatusCurrent && entry.Status != g.StatusIgnored:
+			modified = append(modified, path)
+		}
+	}
+	return added, modified, deleted, nil
 }
 
 // See https://github.com/libgit2/libgit2/blob/091165c53b2bcd5d41fb71d43ed5a23a3d96bf5d/tests/object/commit/commitstagedfile.c#L21-L134
@@ -152,63 +240,335 @@ func (gitRepo *GitRepo) GitStatusAndCommit() error {
 func (gitRepo *GitRepo) addToIndex(path string) error {
 
 	log.Printf("Adding %s to index\n", path)
-	index, err := gitRepo.repo.Index()
-	checkErr(err)
-	// add all will handle the case where path is a directory
-	err = index.AddAll([]string{path}, g.IndexAddDefault, nil)
-	checkErr(err)
-	err = index.Write()
-	checkErr(err)
+	err := gitRepo.retryIfLocked(func() error {
+		index, err := gitRepo.repo.Index()
+		if err != nil {
+			return err
+		}
+		defer index.Free()
+		// add all will handle the case where path is a directory
+		if err = index.AddAll([]string{path}, g.IndexAddDefault, nil); err != nil {
+			return err
+		}
+		return index.Write()
+	})
+	if err != nil {
+		return &GitError{Op: "add", Name: path, Err: err}
+	}
 	return nil
 }
 
 // remove a file from the index. Equivalent to git rm file
 func (gitRepo *GitRepo) removeFromIndex(path string) error {
 	log.Printf("removing %s from index\n", path)
-	index, err := gitRepo.repo.Index()
-	checkErr(err)
-	err = index.RemoveByPath(path)
-	checkErr(err)
-	err = index.Write()
-	checkErr(err)
+	err := gitRepo.retryIfLocked(func() error {
+		index, err := gitRepo.repo.Index()
+		if err != nil {
+			return err
+		}
+		defer index.Free()
+		if err = index.RemoveByPath(path); err != nil {
+			return err
+		}
+		return index.Write()
+	})
+	if err != nil {
+		return &GitError{Op: "rm", Name: path, Err: err}
+	}
 	return nil
 }
 
-// Commit current index to the repo
-func (gitRepo *GitRepo) Commit(message string) error {
+// Commit current index to the repo. Returns the new commit id. If the index has no changes
+// no commit is made and the current HEAD commit id is returned. The server is the committer, and
+// the author if author is nil or not valid.
+func (gitRepo *GitRepo) Commit(message string, author *Author) (string, error) {
+	return gitRepo.commitPaths(message, author, nil)
+}
+
+// commitPaths commits the index entries under paths, on top of HEAD. Changes staged elsewhere in the index are
+// left staged but not committed. If no paths are given the whole index is committed.
+func (gitRepo *GitRepo) commitPaths(message string, author *Author, paths []string) (string, error) {
 
-	sig := &g.Signature{
-		Name:  "config-saver",
-		Email: "config-saver@forgerock.com",
+	now := time.Now()
+	committer := &g.Signature{Name: serverAuthor.Name, Email: serverAuthor.Email, When: now}
+	sig := committer
+	if author != nil && author.Valid() {
+		sig = &g.Signature{Name: author.Name, Email: author.Email, When: now}
+	}
+	var treeId *g.Oid
+	err := gitRepo.retryIfLocked(func() error {
+		index, err := gitRepo.repo.Index()
+		if err != nil {
+			return err
+		}
+		defer index.Free()
+		if treeId, err = gitRepo.writeTree(index, paths); err != nil {
+			return err
+		}
+		return index.Write()
+	})
+	if err != nil {
+		return "", &GitError{Op: "commit", Err: err}
 	}
-	index, err := gitRepo.repo.Index()
-	checkErr(err)
-	treeId, err := index.WriteTree()
-	checkErr(err)
 	tree, err := gitRepo.repo.LookupTree(treeId)
-	checkErr(err)
-	err = index.Write()
-	checkErr(err)
+	if err != nil {
+		return "", &GitError{Op: "commit", Err: err}
+	}
+	defer tree.Free()
 	currentBranch, err := gitRepo.repo.Head()
-	checkErr(err)
+	if err != nil {
+		return "", &GitError{Op: "commit", Err: err}
+	}
+	defer currentBranch.Free()
 
 	currentTip, err := gitRepo.repo.LookupCommit(currentBranch.Target())
-	checkErr(err)
+	if err != nil {
+		return "", &GitError{Op: "commit", Err: err}
+	}
+	defer currentTip.Free()
+
+	if currentTip.TreeId().Equal(treeId) {
+		log.Printf("Nothing to commit")
+		return currentTip.Id().String(), nil
+	}
 
-	_, err = gitRepo.repo.CreateCommit("HEAD", sig, sig, message, tree, currentTip)
-	checkErr(err)
-	return err
+	commitId, err := gitRepo.repo.CreateCommit("HEAD", sig, committer, message, tree, currentTip)
+	if err != nil {
+		if g.IsErrorCode(err, g.ErrorCodeLocked) {
+			err = fmt.Errorf("%w: %v", ErrLocked, err)
+		}
+		return "", &GitError{Op: "commit", Err: err}
+	}
+	return commitId.String(), nil
 }
 
-func checkErr(err error) {
+// writeTree writes the tree to commit: the HEAD tree with the index entries under paths, or the whole index if
+// no paths are given.
+func (gitRepo *GitRepo) writeTree(index *g.Index, paths []string) (*g.Oid, error) {
+	for _, p := range paths {
+		if p == "" || p == "." {
+			paths = nil
+			break
+		}
+	}
+	if len(paths) == 0 {
+		return index.WriteTree()
+	}
+
+	head, err := gitRepo.resolveCommit("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	defer head.Free()
+	headTree, err := head.Tree()
+	if err != nil {
+		return nil, err
+	}
+	defer headTree.Free()
+
+	scoped, err := g.NewIndex()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	defer scoped.Free()
+	if err = scoped.ReadTree(headTree); err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		p = strings.TrimSuffix(p, "/")
+		for _, name := range indexPaths(scoped, p) {
+			if err = scoped.RemoveByPath(name); err != nil {
+				return nil, err
+			}
+		}
+		for _, name := range indexPaths(index, p) {
+			entry, err := index.EntryByPath(name, 0)
+			if err != nil {
+				return nil, fmt.Errorf("could not read %s from the index: %v", name, err)
+			}
+			if err = scoped.Add(entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return scoped.WriteTreeTo(gitRepo.repo)
+}
+
+// RestorePath restores the files under dir in the working tree and index to revision rev (a commit, branch or tag).
+// Files under dir that did not exist at rev are removed. The changes are not committed.
+// Returns the commit id rev resolved to.
+func (gitRepo *GitRepo) RestorePath(rev, dir string) (string, error) {
+	commit, err := gitRepo.resolveCommit(rev)
+	if err != nil {
+		return "", err
+	}
+	defer commit.Free()
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", err
+	}
+	defer tree.Free()
+
+	log.Printf("Restoring %s to commit %s\n", dir, commit.Id())
+	opts := &g.CheckoutOpts{
+		Strategy: g.CheckoutForce | g.CheckoutRemoveUntracked,
+		Paths:    []string{dir},
+	}
+	if err = gitRepo.repo.CheckoutTree(tree, opts); err != nil {
+		if g.IsErrorCode(err, g.ErrorCodeConflict) {
+			return "", fmt.Errorf("%w: could not restore %s to %s: %v", ErrConflict, dir, rev, err)
+		}
+		return "", fmt.Errorf("could not restore %s to %s: %v", dir, rev, err)
+	}
+	return commit.Id().String(), nil
+}
+
+// Push the local branch to the same branch on origin. Equivalent to git push origin branch:branch
+// A push that the remote rejects (for example, a non fast forward update) is returned as an error.
+func (gitRepo *GitRepo) Push() error {
+	remote, err := gitRepo.repo.Remotes.Lookup("origin")
+	if err != nil {
+		return &GitError{Op: "push", Name: "origin", Err: err}
+	}
+	defer remote.Free()
+
+	var rejected []string
+	callbacks, err := remoteCallbacks()
+	if err != nil {
+		return &GitError{Op: "push", Name: gitRepo.Branch, Err: err}
+	}
+	// status is empty if the remote accepted the reference update
+	callbacks.PushUpdateReferenceCallback = func(refname, status string) g.ErrorCode {
+		if status != "" {
+			rejected = append(rejected, fmt.Sprintf("%s (%s)", refname, status))
+		}
+		return g.ErrorCodeOK
+	}
+
+	refspec := fmt.Sprintf("refs/heads/%s:refs/heads/%s", gitRepo.Branch, gitRepo.Branch)
+	log.Printf("Pushing %s to %s\n", refspec, gitRepo.RemoteUrl)
+	err = remote.Push([]string{refspec}, &g.PushOptions{RemoteCallbacks: callbacks})
+	if g.IsErrorCode(err, g.ErrorCodeNonFastForward) {
+		// checked before the push is sent, when the remote branch is known to have moved on
+		return &GitError{Op: "push", Name: gitRepo.Branch, Err: fmt.Errorf("%w: %v", ErrPushRejected, err)}
+	}
+	if err != nil {
+		return &GitError{Op: "push", Name: gitRepo.Branch, Err: err}
+	}
+	if len(rejected) > 0 {
+		return &GitError{Op: "push", Name: gitRepo.Branch, Err: fmt.Errorf("%w: %s", ErrPushRejected, strings.Join(rejected, ", "))}
+	}
+	return nil
+}
+
+// NeedsPush returns true if the local branch has commits that are not on origin, or if
+// the branch has not been pushed to origin yet.
+func (gitRepo *GitRepo) NeedsPush() (bool, error) {
+	local, err := gitRepo.repo.References.Lookup("refs/heads/" + gitRepo.Branch)
+	if err != nil {
+		return false, &GitError{Op: "push status", Name: gitRepo.Branch, Err: err}
+	}
+	defer local.Free()
+
+	upstream, err := gitRepo.repo.References.Lookup("refs/remotes/origin/" + gitRepo.Branch)
+	if err != nil {
+		// The branch does not exist upstream yet
+		return true, nil
+	}
+	defer upstream.Free()
+
+	ahead, _, err := gitRepo.repo.AheadBehind(local.Target(), upstream.Target())
+	if err != nil {
+		return false, &GitError{Op: "push status", Name: gitRepo.Branch, Err: err}
+	}
+	return ahead > 0, nil
+}
+
+// HeadCommitId returns the commit id (sha) of HEAD
+func (gitRepo *GitRepo) HeadCommitId() (string, error) {
+	head, err := gitRepo.repo.Head()
+	if err != nil {
+		return "", err
+	}
+	defer head.Free()
+	return head.Target().String(), nil
+}
+
+// TreeFiles returns the contents of the files under the directory dir at revision rev. The revision can
+// be a commit id, branch or tag. The files are keyed by their path relative to dir.
+// The commit id the revision resolved to is also returned.
+func (gitRepo *GitRepo) TreeFiles(rev, dir string) (string, map[string][]byte, error) {
+	commit, err := gitRepo.resolveCommit(rev)
+	if err != nil {
+		return "", nil, err
+	}
+	defer commit.Free()
+
+	tree, err := gitRepo.subTree(commit, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tree.Free()
+
+	files := make(map[string][]byte)
+	err = tree.Walk(func(root string, entry *g.TreeEntry) int {
+		if entry.Type != g.ObjectBlob {
+			return 0
+		}
+		blob, err := gitRepo.repo.LookupBlob(entry.Id)
+		if err != nil {
+			log.Printf("could not read blob %s%s: %v", root, entry.Name, err)
+			return -1
+		}
+		defer blob.Free()
+		files[root+entry.Name] = blob.Contents()
+		return 0
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("could not read tree %s at %s: %v", dir, rev, err)
+	}
+	return commit.Id().String(), files, nil
+}
+
+// resolveCommit finds the commit for a revision (commit id, branch, tag or any other git revision syntax).
+func (gitRepo *GitRepo) resolveCommit(rev string) (*g.Commit, error) {
+	obj, err := gitRepo.repo.RevparseSingle(rev)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrRevisionNotFound, rev, err)
+	}
+	defer obj.Free()
+	commitObj, err := obj.Peel(g.ObjectCommit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s does not refer to a commit: %v", ErrRevisionNotFound, rev, err)
+	}
+	defer commitObj.Free()
+	return commitObj.AsCommit()
+}
+
+// subTree returns the tree for the directory dir in the commit. An empty dir returns the root tree.
+func (gitRepo *GitRepo) subTree(commit *g.Commit, dir string) (*g.Tree, error) {
+	tree, err := commit.Tree()
+	if err != nil || dir == "" {
+		return tree, err
+	}
+	defer tree.Free()
+	entry, err := tree.EntryByPath(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s in commit %s: %v", ErrPathNotFound, dir, commit.Id(), err)
+	}
+	if entry.Type != g.ObjectTree {
+		return nil, fmt.Errorf("%w: %s is not a directory in commit %s", ErrPathNotFound, dir, commit.Id())
+	}
+	return gitRepo.repo.LookupTree(entry.Id)
 }
 
 // From https://gist.github.com/danielfbm/ba4ae91efa96bb4771351bdbd2c8b06f
 
-func checkoutBranch(repo *g.Repository, branchName string) error {
+// checkoutBranch switches the working tree to branchName. If the branch does not exist on origin, it
+// is created from baseRef (a branch or tag) and set to track origin/branchName. The upstream branch
+// is created the first time the branch is pushed.
+func checkoutBranch(repo *g.Repository, branchName, baseRef string) error {
 	checkoutOpts := &g.CheckoutOpts{
 		Strategy: g.CheckoutSafe | g.CheckoutRecreateMissing | g.CheckoutAllowConflicts | g.CheckoutUseTheirs,
 		ProgressCallback: func(path string, completed, total uint) g.ErrorCode {
@@ -216,29 +576,16 @@ func checkoutBranch(repo *g.Repository, branchName string) error {
 			return 0
 		},
 	}
-	//Getting the reference for the remote branch
-	// remoteBranch, err := repo.References.Lookup("refs/remotes/origin/" + branchName)
-	remoteBranch, err := repo.LookupBranch("origin/"+branchName, g.BranchRemote)
-	if err != nil {
-		log.Print("Failed to find remote branch: " + branchName)
-		// TODO: This fails if the remote branch does not exist (examp]e: origin/autosave)
-		// Instead of generating an error here we should attempt to create the remote branch
-		// git push --set-uptream-to=origin/autosave
-		return err
-	}
-	defer remoteBranch.Free()
-
-	// Lookup for commit from remote branch
-	commit, err := repo.LookupCommit(remoteBranch.Target())
-	if err != nil {
-		log.Print("Failed to find remote branch commit: " + branchName)
-		return err
-	}
-	defer commit.Free()
 
 	localBranch, err := repo.LookupBranch(branchName, g.BranchLocal)
 	// No local branch, lets create one
 	if localBranch == nil || err != nil {
+		commit, err := branchStartCommit(repo, branchName, baseRef)
+		if err != nil {
+			return err
+		}
+		defer commit.Free()
+
 		// Creating local branch
 		localBranch, err = repo.CreateBranch(branchName, commit, false)
 		if err != nil {
@@ -246,9 +593,9 @@ func checkoutBranch(repo *g.Repository, branchName string) error {
 			return err
 		}
 
-		// Setting upstream to origin branch
-		err = localBranch.SetUpstream("origin/" + branchName)
-		if err != nil {
+		// Setting upstream to origin branch. This is the equivalent of git branch --set-upstream-to. We
+		// write the config directly as the remote branch may not exist until we push.
+		if err = setUpstream(repo, branchName); err != nil {
 			log.Print("Failed to create upstream to origin/" + branchName)
 			return err
 		}
@@ -280,6 +627,53 @@ func checkoutBranch(repo *g.Repository, branchName string) error {
 		return err
 	}
 	// Setting the Head to point to our branch
-	repo.SetHead("refs/heads/" + branchName)
-	return nil
+	return repo.SetHead("refs/heads/" + branchName)
+}
+
+// branchStartCommit finds the commit a new local branch should start from. This is the tip of
+// origin/branchName if it exists, otherwise the base ref (a remote branch, local branch or tag).
+func branchStartCommit(repo *g.Repository, branchName, baseRef string) (*g.Commit, error) {
+	//Getting the reference for the remote branch
+	remoteBranch, err := repo.LookupBranch("origin/"+branchName, g.BranchRemote)
+	if err == nil {
+		defer remoteBranch.Free()
+		commit, err := repo.LookupCommit(remoteBranch.Target())
+		if err != nil {
+			log.Print("Failed to find remote branch commit: " + branchName)
+			return nil, err
+		}
+		return commit, nil
+	}
+
+	log.Printf("Remote branch origin/%s not found. Creating it from %s", branchName, baseRef)
+	obj, err := repo.RevparseSingle("origin/" + baseRef)
+	if err != nil {
+		// Not a remote branch, try a tag or local branch
+		obj, err = repo.RevparseSingle(baseRef)
+		if err != nil {
+			return nil, fmt.Errorf("could not find base branch or tag %s: %v", baseRef, err)
+		}
+	}
+	defer obj.Free()
+
+	// tags need to be peeled back to the commit they point to
+	commitObj, err := obj.Peel(g.ObjectCommit)
+	if err != nil {
+		return nil, fmt.Errorf("%s does not refer to a commit: %v", baseRef, err)
+	}
+	defer commitObj.Free()
+	return commitObj.AsCommit()
+}
+
+// setUpstream sets the local branch to track the branch of the same name on origin
+func setUpstream(repo *g.Repository, branchName string) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	defer cfg.Free()
+	if err = cfg.SetString("branch."+branchName+".remote", "origin"); err != nil {
+		return err
+	}
+	return cfg.SetString("branch."+branchName+".merge", "refs/heads/"+branchName)
 }
@@ -0,0 +1,291 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	g "github.com/libgit2/git2go/v31"
+)
+
+// newUpstream creates a bare repository with one commit on master, for the tests to clone
+func newUpstream(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	bare := filepath.Join(dir, "upstream.git")
+	upstream, err := g.InitRepository(bare, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer upstream.Free()
+
+	seedPath := filepath.Join(dir, "seed")
+	seed, err := g.InitRepository(seedPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer seed.Free()
+	if err = os.WriteFile(filepath.Join(seedPath, "README"), []byte("config\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := seed.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Free()
+	if err = index.AddByPath("README"); err != nil {
+		t.Fatal(err)
+	}
+	treeId, err := index.WriteTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := seed.LookupTree(treeId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Free()
+	sig := &g.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	if _, err = seed.CreateCommit("refs/heads/master", sig, sig, "initial", tree); err != nil {
+		t.Fatal(err)
+	}
+
+	remote, err := seed.Remotes.Create("origin", bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Free()
+	if err = remote.Push([]string{"refs/heads/master:refs/heads/master"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err = upstream.SetHead("refs/heads/master"); err != nil {
+		t.Fatal(err)
+	}
+	return bare
+}
+
+// openClone clones the upstream repository and checks out the branch, creating it from master
+func openClone(t *testing.T, upstream, branch string) *GitRepo {
+	t.Helper()
+	t.Setenv("GIT_REPO", upstream)
+	t.Setenv("GIT_SSH_PATH", "")
+	repo, err := OpenGitRepo(filepath.Join(t.TempDir(), "repo"), branch, "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(repo.repo.Free)
+	return repo
+}
+
+// commitFile writes a file to the working tree and commits it
+func commitFile(t *testing.T, repo *GitRepo, name, contents string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo.LocalPath, name), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commitId, err := repo.GitStatusAndCommit("update "+name, nil, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return commitId
+}
+
+func checkNeedsPush(t *testing.T, repo *GitRepo, want bool) {
+	t.Helper()
+	needsPush, err := repo.NeedsPush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if needsPush != want {
+		t.Errorf("NeedsPush() = %v, want %v", needsPush, want)
+	}
+}
+
+func TestPush(t *testing.T) {
+	upstream := newUpstream(t)
+	repo := openClone(t, upstream, "autosave")
+
+	// the branch is not on the remote yet
+	checkNeedsPush(t, repo, true)
+	if err := repo.Push(); err != nil {
+		t.Fatalf("Push() = %v", err)
+	}
+	checkNeedsPush(t, repo, false)
+
+	commitId := commitFile(t, repo, "am.json", `{"a": 1}`)
+	checkNeedsPush(t, repo, true)
+	if err := repo.Push(); err != nil {
+		t.Fatalf("Push() = %v", err)
+	}
+	checkNeedsPush(t, repo, false)
+
+	bare, err := g.OpenRepository(upstream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bare.Free()
+	ref, err := bare.References.Lookup("refs/heads/autosave")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ref.Free()
+	if got := ref.Target().String(); got != commitId {
+		t.Errorf("remote branch is at %s, want %s", got, commitId)
+	}
+}
+
+func TestPushRejected(t *testing.T) {
+	upstream := newUpstream(t)
+	first := openClone(t, upstream, "autosave")
+	if err := first.Push(); err != nil {
+		t.Fatalf("Push() = %v", err)
+	}
+
+	// another server pushes to the branch first
+	second := openClone(t, upstream, "autosave")
+	commitFile(t, second, "idm.json", `{"b": 2}`)
+	if err := second.Push(); err != nil {
+		t.Fatalf("Push() = %v", err)
+	}
+
+	commitFile(t, first, "am.json", `{"a": 1}`)
+	err := first.Push()
+	if !errors.Is(err, ErrPushRejected) {
+		t.Fatalf("Push() = %v, want ErrPushRejected", err)
+	}
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) || gitErr.Op != "push" {
+		t.Errorf("Push() = %#v, want a push GitError", err)
+	}
+	checkNeedsPush(t, first, true)
+}
+
+func TestGitStatusAndCommitPaths(t *testing.T) {
+	upstream := newUpstream(t)
+	repo := openClone(t, upstream, "autosave")
+	for _, dir := range []string{"am", "idm"} {
+		if err := os.Mkdir(filepath.Join(repo.LocalPath, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitFile(t, repo, "am/am.json", `{"a": 1}`)
+
+	// a change to idm is staged, but is not part of the am commit
+	if err := os.WriteFile(filepath.Join(repo.LocalPath, "idm/idm.json"), []byte(`{"b": 2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.addToIndex("idm/idm.json"); err != nil {
+		t.Fatal(err)
+	}
+	// am.json is staged, then changed again in the working tree
+	if err := os.WriteFile(filepath.Join(repo.LocalPath, "am/am.json"), []byte(`{"a": 2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.addToIndex("am/am.json"); err != nil {
+		t.Fatal(err)
+	}
+	commitId := commitFile(t, repo, "am/am.json", `{"a": 3}`)
+
+	_, files, err := repo.TreeFiles(commitId, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(files["am/am.json"]); got != `{"a": 3}` {
+		t.Errorf("am/am.json = %s, want the working tree contents", got)
+	}
+	if _, ok := files["idm/idm.json"]; ok {
+		t.Errorf("idm/idm.json was committed with the am changes")
+	}
+	added, _, _, err := repo.Changes("idm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0] != "idm/idm.json" {
+		t.Errorf("Changes(idm) added = %v, want the staged file", added)
+	}
+}
+
+func TestCommitAuthor(t *testing.T) {
+	tests := []struct {
+		name   string
+		author *Author
+		want   Author
+	}{
+		{name: "no author", want: serverAuthor},
+		{name: "name and email", author: &Author{Name: "Ann", Email: "ann@example.com"}, want: Author{Name: "Ann", Email: "ann@example.com"}},
+		{name: "name and an empty email", author: &Author{Name: "Ann"}, want: serverAuthor},
+		{name: "email in the name", author: &Author{Name: "Ann <ann@example.com>", Email: "ann@example.com"}, want: serverAuthor},
+		{name: "brackets in the email", author: &Author{Name: "Ann", Email: "<ann@example.com>"}, want: serverAuthor},
+	}
+	upstream := newUpstream(t)
+	repo := openClone(t, upstream, "autosave")
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := fmt.Sprintf("file%d.json", i)
+			if err := os.WriteFile(filepath.Join(repo.LocalPath, name), []byte(`{}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+			commitId, err := repo.GitStatusAndCommit("update "+name, tt.author, name)
+			if err != nil {
+				t.Fatalf("GitStatusAndCommit() = %v", err)
+			}
+			commit, err := repo.resolveCommit(commitId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer commit.Free()
+			got := Author{Name: commit.Author().Name, Email: commit.Author().Email}
+			if got != tt.want {
+				t.Errorf("author = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffFilesWritesNoObjects(t *testing.T) {
+	upstream := newUpstream(t)
+	repo := openClone(t, upstream, "autosave")
+	data := []byte(`{"dry": "run"}`)
+
+	diffs, _, err := repo.DiffFiles("HEAD", "", map[string][]byte{"am.json": data}, nil)
+	if err != nil {
+		t.Fatalf("DiffFiles() = %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Path != "am.json" || diffs[0].Change != Added {
+		t.Errorf("DiffFiles() = %+v, want am.json added", diffs)
+	}
+
+	odb, err := repo.repo.Odb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer odb.Free()
+	blobId, err := odb.Hash(data, g.ObjectBlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if odb.Exists(blobId) {
+		t.Errorf("DiffFiles() wrote blob %s to the repository", blobId)
+	}
+}
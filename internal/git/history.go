@@ -0,0 +1,342 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	g "github.com/libgit2/git2go/v31"
+)
+
+// Revision is a commit that changed configuration files
+type Revision struct {
+	CommitId    string
+	AuthorName  string
+	AuthorEmail string
+	When        time.Time
+	Message     string
+	// Files changed by the commit, relative to the directory passed to Log
+	Files []string
+}
+
+// Log returns up to limit commits, newest first, that changed files under the directory dir. The history is read
+// from the commit from, or HEAD if from is empty. If after is not empty, the log starts with the commit following after. This is used to page
+// through the history; the walk starts from after rather than from the top again.
+func (gitRepo *GitRepo) Log(dir, from, after string, limit int) ([]Revision, error) {
+	if from == "" {
+		from = "HEAD"
+	}
+	start, err := gitRepo.resolveCommit(from)
+	if err != nil {
+		return nil, err
+	}
+	defer start.Free()
+
+	starts := []*g.Oid{start.Id()}
+	if after != "" {
+		afterCommit, err := gitRepo.resolveCommit(after)
+		if err != nil {
+			return nil, err
+		}
+		defer afterCommit.Free()
+		inHistory := afterCommit.Id().Equal(start.Id())
+		if !inHistory {
+			inHistory, err = gitRepo.repo.DescendantOf(start.Id(), afterCommit.Id())
+		}
+		if err != nil || !inHistory {
+			return nil, fmt.Errorf("%w: commit %s is not in the history of %s", ErrRevisionNotFound, after, dir)
+		}
+		// walk from the parents of the last commit the caller saw
+		starts = starts[:0]
+		for i := uint(0); i < afterCommit.ParentCount(); i++ {
+			starts = append(starts, afterCommit.ParentId(i))
+		}
+	}
+
+	revisions := make([]Revision, 0)
+	err = gitRepo.walkChanges(starts, nil, dir, func(commit *g.Commit) (bool, error) {
+		files, err := gitRepo.commitChanges(commit, dir)
+		if err != nil {
+			return false, err
+		}
+		if len(files) == 0 {
+			return true, nil
+		}
+		author := commit.Author()
+		revisions = append(revisions, Revision{
+			CommitId:    commit.Id().String(),
+			AuthorName:  author.Name,
+			AuthorEmail: author.Email,
+			When:        author.When,
+			Message:     commit.Message(),
+			Files:       files,
+		})
+		return len(revisions) < limit, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not walk the git history of %s: %v", dir, err)
+	}
+	return revisions, nil
+}
+
+// lastChange is the last commit that changed a directory, as of a head commit
+type lastChange struct {
+	head     string
+	commitId string
+}
+
+// LastChange returns the last commit, at or before head, that changed files under dir. It returns an empty string
+// if no commit did. Results are cached, so later calls only walk the commits added since the previous head.
+func (gitRepo *GitRepo) LastChange(head, dir string) (string, error) {
+	headCommit, err := gitRepo.resolveCommit(head)
+	if err != nil {
+		return "", err
+	}
+	defer headCommit.Free()
+	headId := headCommit.Id()
+
+	gitRepo.historyLock.Lock()
+	cached, ok := gitRepo.lastChanges[dir]
+	gitRepo.historyLock.Unlock()
+	if ok && cached.head == headId.String() {
+		return cached.commitId, nil
+	}
+
+	// If the history has only grown since, walk the new commits. A rebase rewrites the history, so walk it all.
+	var hide *g.Oid
+	commitId := ""
+	if ok {
+		cachedHead, err := g.NewOid(cached.head)
+		if err == nil {
+			if descends, err := gitRepo.repo.DescendantOf(headId, cachedHead); err == nil && descends {
+				hide = cachedHead
+				commitId = cached.commitId
+			}
+		}
+	}
+	err = gitRepo.walkChanges([]*g.Oid{headId}, hide, dir, func(commit *g.Commit) (bool, error) {
+		commitId = commit.Id().String()
+		return false, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not walk the git history of %s: %v", dir, err)
+	}
+
+	gitRepo.historyLock.Lock()
+	defer gitRepo.historyLock.Unlock()
+	if gitRepo.lastChanges == nil {
+		gitRepo.lastChanges = make(map[string]lastChange)
+	}
+	gitRepo.lastChanges[dir] = lastChange{head: headId.String(), commitId: commitId}
+	return commitId, nil
+}
+
+// walkChanges walks the history from the start commits, newest first, and calls fn with each commit that changed
+// files under dir, until fn returns false. Commits reachable from hide, if it is not nil, are not walked. Only the
+// id of dir is compared with the commit's first parent, so a commit that did not change dir costs a tree lookup
+// rather than a diff.
+func (gitRepo *GitRepo) walkChanges(starts []*g.Oid, hide *g.Oid, dir string, fn func(*g.Commit) (bool, error)) error {
+	if len(starts) == 0 {
+		return nil
+	}
+	walk, err := gitRepo.repo.Walk()
+	if err != nil {
+		return err
+	}
+	defer walk.Free()
+	walk.Sorting(g.SortTopological | g.SortTime)
+	for _, start := range starts {
+		if err = walk.Push(start); err != nil {
+			return err
+		}
+	}
+	if hide != nil {
+		if err = walk.Hide(hide); err != nil {
+			return err
+		}
+	}
+
+	var walkErr error
+	err = walk.Iterate(func(commit *g.Commit) bool {
+		defer commit.Free()
+		changed, err := changedDir(commit, dir)
+		if err != nil {
+			walkErr = err
+			return false
+		}
+		if !changed {
+			return true
+		}
+		more, err := fn(commit)
+		if err != nil {
+			walkErr = err
+			return false
+		}
+		return more
+	})
+	if err == nil {
+		err = walkErr
+	}
+	return err
+}
+
+// changedDir returns true if the commit changed anything under dir, compared to its first parent
+func changedDir(commit *g.Commit, dir string) (bool, error) {
+	id, err := dirId(commit, dir)
+	if err != nil {
+		return false, err
+	}
+	if commit.ParentCount() == 0 {
+		return id != nil, nil
+	}
+	parent := commit.Parent(0)
+	if parent == nil {
+		return false, fmt.Errorf("could not read the parent of commit %s", commit.Id())
+	}
+	defer parent.Free()
+	parentId, err := dirId(parent, dir)
+	if err != nil {
+		return false, err
+	}
+	if id == nil || parentId == nil {
+		return id != parentId, nil
+	}
+	return !id.Equal(parentId), nil
+}
+
+// dirId returns the id of the tree (or file) at dir in the commit, or nil if the commit does not have dir
+func dirId(commit *g.Commit, dir string) (*g.Oid, error) {
+	dir = strings.Trim(dir, "/")
+	if dir == "" {
+		return commit.TreeId(), nil
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Free()
+	entry, err := tree.EntryByPath(dir)
+	if g.IsErrorCode(err, g.ErrorCodeNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry.Id, nil
+}
+
+// commitChanges returns the files under dir changed by the commit, compared to its first parent.
+func (gitRepo *GitRepo) commitChanges(commit *g.Commit, dir string) ([]string, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Free()
+
+	// The first commit in the repo has no parent. Diff against an empty tree
+	var parentTree *g.Tree
+	if commit.ParentCount() > 0 {
+		parent := commit.Parent(0)
+		defer parent.Free()
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+		defer parentTree.Free()
+	}
+	return gitRepo.changedFiles(parentTree, tree, dir)
+}
+
+// ChangedFiles returns the files that were added or modified, and the files that were deleted, between two
+// revisions (commit, branch or tag). Paths are relative to the root of the repo.
+func (gitRepo *GitRepo) ChangedFiles(from, to string) ([]string, []string, error) {
+	fromCommit, err := gitRepo.resolveCommit(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fromCommit.Free()
+	toCommit, err := gitRepo.resolveCommit(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer toCommit.Free()
+
+	fromTree, err := fromCommit.Tree()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fromTree.Free()
+	toTree, err := toCommit.Tree()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer toTree.Free()
+	return gitRepo.fileChanges(fromTree, toTree, "")
+}
+
+// changedFiles returns the paths, relative to dir, of the files under dir that differ between the two trees.
+func (gitRepo *GitRepo) changedFiles(oldTree, newTree *g.Tree, dir string) ([]string, error) {
+	changed, deleted, err := gitRepo.fileChanges(oldTree, newTree, dir)
+	return append(changed, deleted...), err
+}
+
+// fileChanges returns the paths, relative to dir, of the files under dir that were added or modified,
+// and the files that were deleted, between the two trees.
+func (gitRepo *GitRepo) fileChanges(oldTree, newTree *g.Tree, dir string) ([]string, []string, error) {
+	opts, err := g.DefaultDiffOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+	if dir != "" {
+		opts.Pathspec = []string{dir}
+	}
+	diff, err := gitRepo.repo.DiffTreeToTree(oldTree, newTree, &opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer diff.Free()
+
+	count, err := diff.NumDeltas()
+	if err != nil {
+		return nil, nil, err
+	}
+	changed := make([]string, 0, count)
+	deleted := make([]string, 0)
+	for i := 0; i < count; i++ {
+		delta, err := diff.Delta(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if delta.Status == g.DeltaDeleted {
+			deleted = append(deleted, relativePath(dir, delta.OldFile.Path))
+		} else {
+			changed = append(changed, relativePath(dir, delta.NewFile.Path))
+		}
+	}
+	return changed, deleted, nil
+}
+
+// relativePath strips the directory dir from a repo path
+func relativePath(dir, path string) string {
+	if dir == "" {
+		return path
+	}
+	return strings.TrimPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
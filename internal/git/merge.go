@@ -0,0 +1,41 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package git
+
+import (
+	g "github.com/libgit2/git2go/v31"
+)
+
+// MergeText performs a line based three-way merge of a file, like git merge does. base is the contents both
+// sides started from (empty if the file was added on both sides). Returns the merged contents and true if the
+// changes merged cleanly, otherwise the contents with conflict markers and false.
+func MergeText(path string, base, ours, theirs []byte) ([]byte, bool, error) {
+	const mode = uint(g.FilemodeBlob)
+	result, err := g.MergeFile(
+		g.MergeFileInput{Path: path, Mode: mode, Contents: base},
+		g.MergeFileInput{Path: path, Mode: mode, Contents: ours},
+		g.MergeFileInput{Path: path, Mode: mode, Contents: theirs},
+		&g.MergeFileOptions{AncestorLabel: "base", OurLabel: "ours", TheirLabel: "theirs"},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	defer result.Free()
+	return result.Contents, result.Automergeable, nil
+}
@@ -0,0 +1,320 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package git
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	g "github.com/libgit2/git2go/v31"
+)
+
+// Pull fetches origin, and brings the local branch up to date with origin/Branch. If the local branch has
+// no commits of its own it is fast forwarded, otherwise the local commits are rebased onto origin/Branch.
+// Returns the commit id of HEAD before and after. If the rebase conflicts it is abandoned, the branch and the
+// working tree are left unchanged, and ErrConflict is returned listing the conflicting files. A working tree with
+// uncommitted changes is not pulled, and ErrConflict is returned listing the changed files.
+func (gitRepo *GitRepo) Pull() (string, string, error) {
+	before, err := gitRepo.HeadCommitId()
+	if err != nil {
+		return "", "", err
+	}
+	uncommitted, err := gitRepo.uncommittedPaths()
+	if err != nil {
+		return before, before, err
+	}
+	if len(uncommitted) > 0 {
+		return before, before, fmt.Errorf("%w: %s has uncommitted changes in %s", ErrConflict, gitRepo.Branch, strings.Join(uncommitted, ", "))
+	}
+	if err = gitRepo.fetch(); err != nil {
+		return before, before, err
+	}
+
+	upstream, err := gitRepo.repo.References.Lookup("refs/remotes/origin/" + gitRepo.Branch)
+	if err != nil {
+		// The branch has not been pushed yet, so there is nothing to pull
+		return before, before, nil
+	}
+	defer upstream.Free()
+	local, err := gitRepo.repo.References.Lookup("refs/heads/" + gitRepo.Branch)
+	if err != nil {
+		return before, before, fmt.Errorf("could not find local branch %s: %v", gitRepo.Branch, err)
+	}
+	defer local.Free()
+
+	ahead, behind, err := gitRepo.repo.AheadBehind(local.Target(), upstream.Target())
+	if err != nil {
+		return before, before, err
+	}
+	switch {
+	case behind == 0:
+		log.Printf("branch %s is up to date with origin", gitRepo.Branch)
+	case ahead == 0:
+		err = gitRepo.fastForward(local, upstream)
+	default:
+		err = gitRepo.rebase(local, upstream)
+	}
+	if err != nil {
+		return before, before, err
+	}
+
+	after, err := gitRepo.HeadCommitId()
+	return before, after, err
+}
+
+// uncommittedPaths returns the files in the index or the working tree that differ from HEAD, including new files
+func (gitRepo *GitRepo) uncommittedPaths() ([]string, error) {
+	opts := &g.StatusOptions{Flags: g.StatusOptIncludeUntracked}
+	list, err := gitRepo.repo.StatusList(opts)
+	if err != nil {
+		return nil, &GitError{Op: "status", Err: err}
+	}
+	defer list.Free()
+
+	count, err := list.EntryCount()
+	if err != nil {
+		return nil, &GitError{Op: "status", Err: err}
+	}
+	var paths []string
+	for i := 0; i < count; i++ {
+		entry, err := list.ByIndex(i)
+		if err != nil {
+			return nil, &GitError{Op: "status", Err: err}
+		}
+		if entry.Status == g.StatusCurrent || entry.Status&g.StatusIgnored != 0 {
+			continue
+		}
+		path := entry.IndexToWorkdir.NewFile.Path
+		if path == "" {
+			path = entry.HeadToIndex.NewFile.Path
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// fetch updates the remote tracking branches from origin. Equivalent to git fetch origin
+func (gitRepo *GitRepo) fetch() error {
+	remote, err := gitRepo.repo.Remotes.Lookup("origin")
+	if err != nil {
+		return fmt.Errorf("could not find remote origin: %v", err)
+	}
+	defer remote.Free()
+	callbacks, err := remoteCallbacks()
+	if err != nil {
+		return &GitError{Op: "fetch", Name: gitRepo.RemoteUrl, Err: err}
+	}
+	log.Printf("Fetching from %s\n", gitRepo.RemoteUrl)
+	if err = remote.Fetch(nil, &g.FetchOptions{RemoteCallbacks: callbacks}, ""); err != nil {
+		return fmt.Errorf("fetch from %s failed: %v", gitRepo.RemoteUrl, err)
+	}
+	return nil
+}
+
+// fastForward moves the local branch to the upstream commit, and checks out its tree. Uncommitted
+// changes are not overwritten.
+func (gitRepo *GitRepo) fastForward(local, upstream *g.Reference) error {
+	log.Printf("Fast forwarding %s to %s\n", gitRepo.Branch, upstream.Target())
+	return gitRepo.moveBranch(local, upstream.Target(), "pull: fast-forward")
+}
+
+// moveBranch checks out the commit's tree, then points the local branch at the commit. The checkout is safe: if
+// it would overwrite uncommitted changes it fails with ErrConflict, and nothing is changed.
+func (gitRepo *GitRepo) moveBranch(local *g.Reference, commitId *g.Oid, reason string) error {
+	commit, err := gitRepo.repo.LookupCommit(commitId)
+	if err != nil {
+		return err
+	}
+	defer commit.Free()
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+	defer tree.Free()
+
+	if err = gitRepo.repo.CheckoutTree(tree, &g.CheckoutOpts{Strategy: g.CheckoutSafe}); err != nil {
+		if g.IsErrorCode(err, g.ErrorCodeConflict) {
+			return fmt.Errorf("%w: could not update %s, it would overwrite uncommitted changes: %v", ErrConflict, gitRepo.Branch, err)
+		}
+		return err
+	}
+	ref, err := local.SetTarget(commit.Id(), reason)
+	if err != nil {
+		return err
+	}
+	ref.Free()
+	return nil
+}
+
+// rebase replays the local commits onto the upstream commit, keeping their authors and messages. The rebase is
+// made in memory, so the working tree and the branch are only changed once it has succeeded. A rebase that
+// conflicts leaves them as they were, including any uncommitted changes.
+func (gitRepo *GitRepo) rebase(local, upstream *g.Reference) error {
+	branch, err := gitRepo.repo.AnnotatedCommitFromRef(local)
+	if err != nil {
+		return err
+	}
+	defer branch.Free()
+	onto, err := gitRepo.repo.AnnotatedCommitFromRef(upstream)
+	if err != nil {
+		return err
+	}
+	defer onto.Free()
+
+	opts, err := g.DefaultRebaseOptions()
+	if err != nil {
+		return err
+	}
+	opts.InMemory = 1
+	log.Printf("Rebasing %s onto origin/%s\n", gitRepo.Branch, gitRepo.Branch)
+	rebase, err := gitRepo.repo.InitRebase(branch, onto, nil, &opts)
+	if err != nil {
+		return fmt.Errorf("could not start rebase of %s: %v", gitRepo.Branch, err)
+	}
+	defer rebase.Free()
+
+	head, err := gitRepo.applyRebase(rebase, upstream.Target())
+	if err != nil {
+		if abortErr := rebase.Abort(); abortErr != nil {
+			log.Printf("could not abort rebase of %s: %v", gitRepo.Branch, abortErr)
+		}
+		return err
+	}
+	if err = rebase.Finish(); err != nil {
+		return err
+	}
+	return gitRepo.moveBranch(local, head, "pull: rebase")
+}
+
+// applyRebase applies each operation of the rebase, stopping at the first conflict. Returns the last commit
+// made, starting from onto.
+func (gitRepo *GitRepo) applyRebase(rebase *g.Rebase, onto *g.Oid) (*g.Oid, error) {
+	committer := &g.Signature{
+		Name:  serverAuthor.Name,
+		Email: serverAuthor.Email,
+		When:  time.Now(),
+	}
+	head := onto
+	for {
+		op, err := rebase.Next()
+		if g.IsErrorCode(err, g.ErrorCodeIterOver) {
+			return head, nil
+		}
+		if err != nil {
+			if g.IsErrorCode(err, g.ErrorCodeConflict) {
+				return nil, fmt.Errorf("%w: rebase of %s: %v", ErrConflict, gitRepo.Branch, err)
+			}
+			return nil, err
+		}
+
+		commit, err := gitRepo.repo.LookupCommit(op.Id)
+		if err != nil {
+			return nil, err
+		}
+		commitId := new(g.Oid)
+		err = rebase.Commit(commitId, commit.Author(), committer, commit.Message())
+		commit.Free()
+		switch {
+		case err == nil:
+			head = commitId
+		case g.IsErrorCode(err, g.ErrorCodeApplied):
+			// a commit whose changes are already upstream is skipped
+		case g.IsErrorCode(err, g.ErrorCodeUnmerged):
+			conflicts, pathErr := gitRepo.pickConflicts(head, op.Id)
+			if pathErr != nil {
+				return nil, fmt.Errorf("%w: rebase of %s onto origin: %v", ErrConflict, gitRepo.Branch, err)
+			}
+			return nil, fmt.Errorf("%w: rebase of %s onto origin conflicts in %s", ErrConflict, gitRepo.Branch, strings.Join(conflicts, ", "))
+		default:
+			return nil, err
+		}
+	}
+}
+
+// pickConflicts returns the paths that conflict when the changes made by the commit are applied to head
+func (gitRepo *GitRepo) pickConflicts(head, commitId *g.Oid) ([]string, error) {
+	commit, err := gitRepo.repo.LookupCommit(commitId)
+	if err != nil {
+		return nil, err
+	}
+	defer commit.Free()
+	if commit.ParentCount() == 0 {
+		return nil, fmt.Errorf("commit %s has no parent", commitId)
+	}
+	parent := commit.Parent(0)
+	if parent == nil {
+		return nil, fmt.Errorf("could not read the parent of commit %s", commitId)
+	}
+	defer parent.Free()
+	headCommit, err := gitRepo.repo.LookupCommit(head)
+	if err != nil {
+		return nil, err
+	}
+	defer headCommit.Free()
+
+	trees := make([]*g.Tree, 0, 3)
+	defer func() {
+		for _, tree := range trees {
+			tree.Free()
+		}
+	}()
+	for _, c := range []*g.Commit{parent, headCommit, commit} {
+		tree, err := c.Tree()
+		if err != nil {
+			return nil, err
+		}
+		trees = append(trees, tree)
+	}
+	index, err := gitRepo.repo.MergeTrees(trees[0], trees[1], trees[2], nil)
+	if err != nil {
+		return nil, err
+	}
+	defer index.Free()
+	return conflictedPaths(index), nil
+}
+
+// conflictedPaths returns the paths with conflicts in the index
+func conflictedPaths(index *g.Index) []string {
+	paths := make([]string, 0)
+	if !index.HasConflicts() {
+		return paths
+	}
+	iterator, err := index.ConflictIterator()
+	if err != nil {
+		return []string{err.Error()}
+	}
+	defer iterator.Free()
+	for {
+		conflict, err := iterator.Next()
+		if err != nil {
+			break
+		}
+		switch {
+		case conflict.Our != nil:
+			paths = append(paths, conflict.Our.Path)
+		case conflict.Their != nil:
+			paths = append(paths, conflict.Their.Path)
+		case conflict.Ancestor != nil:
+			paths = append(paths, conflict.Ancestor.Path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,341 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package merge implements three-way merges of configuration files.
+package merge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidJSON is returned when a file to merge is not a JSON document
+var ErrInvalidJSON = errors.New("invalid json")
+
+// A Conflict is a value changed differently on both sides of a merge
+type Conflict struct {
+	// JSON pointer (RFC 6901) to the value. Empty for the whole document.
+	Pointer string
+	// The values, as JSON, in the base and on each side. Empty if the value is not present.
+	Base, Ours, Theirs string
+}
+
+// object is a JSON object that keeps the order of its keys, so merged files have the
+// same layout as the originals
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// JSON merges the changes made to a JSON document on two sides, ours and theirs. The base is the document both
+// sides started from, or nil if the document was added on both sides. Objects are merged key by key; other
+// values, including arrays, are replaced as a whole. The merged document keeps the key order and formatting of
+// theirs. Values changed differently on both sides are returned as conflicts.
+func JSON(base, ours, theirs []byte) ([]byte, []Conflict, error) {
+	var baseValue interface{}
+	baseOk := base != nil
+	if baseOk {
+		var err error
+		if baseValue, err = parse(base); err != nil {
+			return nil, nil, err
+		}
+	}
+	oursValue, err := parse(ours)
+	if err != nil {
+		return nil, nil, err
+	}
+	theirsValue, err := parse(theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := &merger{}
+	merged, _ := m.merge("", baseValue, baseOk, oursValue, true, theirsValue, true)
+	var buf bytes.Buffer
+	detectStyle(theirs).encode(&buf, merged, 0)
+	if bytes.HasSuffix(theirs, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), m.conflicts, nil
+}
+
+type merger struct {
+	conflicts []Conflict
+}
+
+// merge returns the merged value at the pointer, and whether it is present. Each value has a flag
+// saying whether it is present on that side.
+func (m *merger) merge(pointer string, base interface{}, baseOk bool, ours interface{}, oursOk bool, theirs interface{}, theirsOk bool) (interface{}, bool) {
+	switch {
+	case same(ours, oursOk, theirs, theirsOk):
+		return ours, oursOk
+	case same(ours, oursOk, base, baseOk):
+		return theirs, theirsOk
+	case same(theirs, theirsOk, base, baseOk):
+		return ours, oursOk
+	}
+
+	oursObject, oursIsObject := ours.(*object)
+	theirsObject, theirsIsObject := theirs.(*object)
+	baseObject, baseIsObject := base.(*object)
+	if oursIsObject && theirsIsObject && (baseIsObject || !baseOk) {
+		if !baseOk {
+			baseObject = &object{values: map[string]interface{}{}}
+		}
+		return m.mergeObjects(pointer, baseObject, oursObject, theirsObject), true
+	}
+
+	m.conflicts = append(m.conflicts, Conflict{
+		Pointer: pointer,
+		Base:    compact(base, baseOk),
+		Ours:    compact(ours, oursOk),
+		Theirs:  compact(theirs, theirsOk),
+	})
+	return ours, oursOk
+}
+
+// mergeObjects merges key by key. The keys are in the order of theirs, followed by keys only in ours.
+func (m *merger) mergeObjects(pointer string, base, ours, theirs *object) *object {
+	merged := &object{values: map[string]interface{}{}}
+	keys := append([]string{}, theirs.keys...)
+	for _, key := range ours.keys {
+		if _, ok := theirs.values[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		baseValue, baseOk := base.values[key]
+		oursValue, oursOk := ours.values[key]
+		theirsValue, theirsOk := theirs.values[key]
+		value, ok := m.merge(pointer+"/"+escapePointer(key), baseValue, baseOk, oursValue, oursOk, theirsValue, theirsOk)
+		if ok {
+			merged.keys = append(merged.keys, key)
+			merged.values[key] = value
+		}
+	}
+	return merged
+}
+
+// escapePointer escapes a key for use in a JSON pointer
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// same returns true if both values are absent, or both are present and equal
+func same(a interface{}, aOk bool, b interface{}, bOk bool) bool {
+	if aOk != bOk {
+		return false
+	}
+	return !aOk || equal(a, b)
+}
+
+// equal compares two JSON values. Object key order does not matter.
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case *object:
+		b, ok := b.(*object)
+		if !ok || len(a.keys) != len(b.keys) {
+			return false
+		}
+		for key, value := range a.values {
+			other, ok := b.values[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		return ok && a == b
+	default:
+		return a == b
+	}
+}
+
+// compact encodes a value on a single line, or returns an empty string if the value is not present
+func compact(value interface{}, ok bool) string {
+	if !ok {
+		return ""
+	}
+	var buf bytes.Buffer
+	style{colon: ":"}.encode(&buf, value, 0)
+	return buf.String()
+}
+
+// parse reads a JSON document, keeping the order of object keys and the text of numbers
+func parse(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := parseValue(decoder)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after the document", ErrInvalidJSON)
+	}
+	return value, nil
+}
+
+func parseValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		obj := &object{values: map[string]interface{}{}}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyToken.(string)
+			value, err := parseValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := obj.values[key]; !ok {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = value
+		}
+		_, err = decoder.Token()
+		return obj, err
+	case json.Delim('['):
+		array := make([]interface{}, 0)
+		for decoder.More() {
+			value, err := parseValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	}
+	return token, nil
+}
+
+// style is the layout of a JSON file. Files written by AM and IDM use the Jackson layout, with
+// "key" : value pairs and arrays on a single line.
+type style struct {
+	indent  string
+	colon   string
+	jackson bool
+}
+
+// detectStyle guesses the layout of a JSON file from its contents
+func detectStyle(data []byte) style {
+	s := style{indent: "  ", colon: ": "}
+	if bytes.Contains(data, []byte(`" : `)) {
+		s.colon = " : "
+		s.jackson = true
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != line && trimmed != "" {
+			s.indent = line[:len(line)-len(trimmed)]
+			break
+		}
+	}
+	return s
+}
+
+// encode writes a value at the nesting depth. An empty indent writes the value on a single line.
+func (s style) encode(buf *bytes.Buffer, value interface{}, depth int) {
+	newline := func(depth int) {
+		if s.indent != "" {
+			buf.WriteByte('\n')
+			buf.WriteString(strings.Repeat(s.indent, depth))
+		}
+	}
+	switch v := value.(type) {
+	case *object:
+		if len(v.keys) == 0 {
+			if s.jackson {
+				buf.WriteString("{ }")
+			} else {
+				buf.WriteString("{}")
+			}
+			return
+		}
+		buf.WriteByte('{')
+		for i, key := range v.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(depth + 1)
+			s.encode(buf, key, depth+1)
+			buf.WriteString(s.colon)
+			s.encode(buf, v.values[key], depth+1)
+		}
+		newline(depth)
+		buf.WriteByte('}')
+	case []interface{}:
+		switch {
+		case len(v) == 0 && s.jackson:
+			buf.WriteString("[ ]")
+		case len(v) == 0:
+			buf.WriteString("[]")
+		case s.jackson:
+			// arrays are on one line, and do not add to the indent of objects in them
+			buf.WriteString("[ ")
+			for i, element := range v {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				s.encode(buf, element, depth)
+			}
+			buf.WriteString(" ]")
+		default:
+			buf.WriteByte('[')
+			for i, element := range v {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				newline(depth + 1)
+				s.encode(buf, element, depth+1)
+			}
+			newline(depth)
+			buf.WriteByte(']')
+		}
+	case json.Number:
+		buf.WriteString(v.String())
+	default:
+		// strings, booleans and null. HTML characters are not escaped, to match the original files.
+		encoder := json.NewEncoder(buf)
+		encoder.SetEscapeHTML(false)
+		_ = encoder.Encode(v)
+		buf.Truncate(buf.Len() - 1)
+	}
+}
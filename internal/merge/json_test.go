@@ -0,0 +1,223 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package merge
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		// the base is nil, the file was added on both sides
+		noBase    bool
+		want      string
+		conflicts []Conflict
+	}{
+		{
+			name: "changes to different keys are merged in the key order of theirs",
+			base: `{"a": 1, "b": 2}`,
+			ours: `{"a": 10, "b": 2, "c": 3}`,
+			theirs: `{
+  "b": 20,
+  "a": 1
+}
+`,
+			want: `{
+  "b": 20,
+  "a": 10,
+  "c": 3
+}
+`,
+		},
+		{
+			name:   "the same change on both sides",
+			base:   `{"a": 1}`,
+			ours:   `{"a": 2}`,
+			theirs: `{"a": 2}`,
+			want: `{
+  "a": 2
+}`,
+		},
+		{
+			name:   "a key removed on one side",
+			base:   `{"a": 1, "b": 2}`,
+			ours:   `{"a": 1}`,
+			theirs: `{"a": 1, "b": 2, "c": 3}`,
+			want: `{
+  "a": 1,
+  "c": 3
+}`,
+		},
+		{
+			name:   "a value changed differently on both sides keeps ours",
+			base:   `{"a": {"b": 1}}`,
+			ours:   `{"a": {"b": 2}}`,
+			theirs: `{"a": {"b": 3}}`,
+			want: `{
+  "a": {
+    "b": 2
+  }
+}`,
+			conflicts: []Conflict{{Pointer: "/a/b", Base: "1", Ours: "2", Theirs: "3"}},
+		},
+		{
+			name:      "a value removed on one side and changed on the other",
+			base:      `{"a": 1}`,
+			ours:      `{}`,
+			theirs:    `{"a": 2}`,
+			want:      `{}`,
+			conflicts: []Conflict{{Pointer: "/a", Base: "1", Theirs: "2"}},
+		},
+		{
+			name:   "arrays are replaced as a whole",
+			base:   `{"a": [1, 2]}`,
+			ours:   `{"a": [1, 2, 3]}`,
+			theirs: `{"a": [0, 1, 2]}`,
+			want: `{
+  "a": [
+    1,
+    2,
+    3
+  ]
+}`,
+			conflicts: []Conflict{{Pointer: "/a", Base: "[1,2]", Ours: "[1,2,3]", Theirs: "[0,1,2]"}},
+		},
+		{
+			name:   "keys in a pointer are escaped",
+			base:   `{"a/b": {"c~d": 1}}`,
+			ours:   `{"a/b": {"c~d": 2}}`,
+			theirs: `{"a/b": {"c~d": 3}}`,
+			want: `{
+  "a/b": {
+    "c~d": 2
+  }
+}`,
+			conflicts: []Conflict{{Pointer: "/a~1b/c~0d", Base: "1", Ours: "2", Theirs: "3"}},
+		},
+		{
+			name:   "objects added on both sides are merged",
+			noBase: true,
+			ours:   `{"a": 1, "b": 2}`,
+			theirs: `{"c": 3, "a": 1}`,
+			want: `{
+  "c": 3,
+  "a": 1,
+  "b": 2
+}`,
+		},
+		{
+			name:   "different values added on both sides",
+			noBase: true,
+			ours:   `{"a": 1}`,
+			theirs: `{"a": 2}`,
+			want: `{
+  "a": 1
+}`,
+			conflicts: []Conflict{{Pointer: "/a", Ours: "1", Theirs: "2"}},
+		},
+		{
+			name:   "numbers keep their text",
+			base:   `{"a": 1.0, "b": 1e3}`,
+			ours:   `{"a": 1.0, "b": 1e3, "c": 0.50}`,
+			theirs: `{"a": 1.0, "b": 1e3}`,
+			want: `{
+  "a": 1.0,
+  "b": 1e3,
+  "c": 0.50
+}`,
+		},
+		{
+			name: "the jackson layout of AM and IDM files",
+			base: `{"a": 1}`,
+			ours: `{"a": 1, "b": [1, {"c": true}], "e": {}, "f": []}`,
+			theirs: `{
+  "a" : 1,
+  "d" : "<x>"
+}`,
+			want: `{
+  "a" : 1,
+  "d" : "<x>",
+  "b" : [ 1, {
+    "c" : true
+  } ],
+  "e" : { },
+  "f" : [ ]
+}`,
+		},
+		{
+			name: "the indent of theirs",
+			base: `{"a": 1}`,
+			ours: `{"a": 2}`,
+			theirs: `{
+	"a": 1,
+	"b": [1]
+}
+`,
+			want: `{
+	"a": 2,
+	"b": [
+		1
+	]
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var base []byte
+			if !tt.noBase {
+				base = []byte(tt.base)
+			}
+			got, conflicts, err := JSON(base, []byte(tt.ours), []byte(tt.theirs))
+			if err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("JSON() = \n%s\nwant\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(conflicts, tt.conflicts) {
+				t.Errorf("JSON() conflicts = %+v, want %+v", conflicts, tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+	}{
+		{name: "invalid ours", base: `{}`, ours: `{"a":`, theirs: `{}`},
+		{name: "invalid theirs", base: `{}`, ours: `{}`, theirs: `not json`},
+		{name: "invalid base", base: `{`, ours: `{}`, theirs: `{}`},
+		{name: "data after the document", base: `{}`, ours: `{} {}`, theirs: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := JSON([]byte(tt.base), []byte(tt.ours), []byte(tt.theirs))
+			if !errors.Is(err, ErrInvalidJSON) {
+				t.Errorf("JSON() error = %v, want ErrInvalidJSON", err)
+			}
+		})
+	}
+}
@@ -1,4 +1,8 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.17.3
+// source: proto/configsaver.proto
 
 package proto
 
@@ -24,6 +28,35 @@ type ConfigSaverClient interface {
 	// The server is responsible for determining whether the configuration is valid, has changed
 	// and how to persist it.
 	UpdateConfig(ctx context.Context, in *UpdateConfigRequest, opts ...grpc.CallOption) (*UpdateConfigReply, error)
+	// Push committed configuration to the upstream git repository.
+	PushConfig(ctx context.Context, in *PushConfigRequest, opts ...grpc.CallOption) (*PushConfigReply, error)
+	// List the commits that changed a product's configuration, newest first.
+	ListRevisions(ctx context.Context, in *ListRevisionsRequest, opts ...grpc.CallOption) (*ListRevisionsReply, error)
+	// Restore a product's configuration to a previous revision. The restored configuration is committed
+	// as a new revision.
+	RollbackConfig(ctx context.Context, in *RollbackConfigRequest, opts ...grpc.CallOption) (*RollbackConfigReply, error)
+	// Compare a product's configuration between two revisions, or between a revision and
+	// configuration uploaded by the client. Nothing is saved.
+	DiffConfig(ctx context.Context, in *DiffConfigRequest, opts ...grpc.CallOption) (*DiffConfigReply, error)
+	// List the products the server has configuration for.
+	ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsReply, error)
+	// Copy the configuration of one product profile to another (for example, cdk to prod).
+	PromoteConfig(ctx context.Context, in *PromoteConfigRequest, opts ...grpc.CallOption) (*PromoteConfigReply, error)
+	// Watch a product's configuration. The server sends an event each time a commit changes files in the
+	// product's configuration directory, until the client cancels the call.
+	WatchConfig(ctx context.Context, in *WatchConfigRequest, opts ...grpc.CallOption) (ConfigSaver_WatchConfigClient, error)
+	// Fetch the upstream git repository, and bring the server's branch up to date with it. Local commits are
+	// rebased onto the upstream branch. Clients watching changed products are notified.
+	PullConfig(ctx context.Context, in *PullConfigRequest, opts ...grpc.CallOption) (*PullConfigReply, error)
+	// GetConfig for configurations larger than the gRPC message size limit. The server sends a header, then the
+	// tar file in chunks, then a trailer with the checksum.
+	GetConfigStream(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (ConfigSaver_GetConfigStreamClient, error)
+	// UpdateConfig for configurations larger than the gRPC message size limit. The client sends a header, then the
+	// tar file in chunks, then a trailer with the checksum. The update is only saved if the checksum matches.
+	UpdateConfigStream(ctx context.Context, opts ...grpc.CallOption) (ConfigSaver_UpdateConfigStreamClient, error)
+	// Compare the client's files, listed with their checksums, with the product's configuration, so the client
+	// only uploads the files the server does not have. Nothing is saved.
+	CompareManifest(ctx context.Context, in *CompareManifestRequest, opts ...grpc.CallOption) (*CompareManifestReply, error)
 }
 
 type configSaverClient struct {
@@ -52,6 +85,176 @@ func (c *configSaverClient) UpdateConfig(ctx context.Context, in *UpdateConfigRe
 	return out, nil
 }
 
+func (c *configSaverClient) PushConfig(ctx context.Context, in *PushConfigRequest, opts ...grpc.CallOption) (*PushConfigReply, error) {
+	out := new(PushConfigReply)
+	err := c.cc.Invoke(ctx, "/configsaver.ConfigSaver/PushConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configSaverClient) ListRevisions(ctx context.Context, in *ListRevisionsRequest, opts ...grpc.CallOption) (*ListRevisionsReply, error) {
+	out := new(ListRevisionsReply)
+	err := c.cc.Invoke(ctx, "/configsaver.ConfigSaver/ListRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configSaverClient) RollbackConfig(ctx context.Context, in *RollbackConfigRequest, opts ...grpc.CallOption) (*RollbackConfigReply, error) {
+	out := new(RollbackConfigReply)
+	err := c.cc.Invoke(ctx, "/configsaver.ConfigSaver/RollbackConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configSaverClient) DiffConfig(ctx context.Context, in *DiffConfigRequest, opts ...grpc.CallOption) (*DiffConfigReply, error) {
+	out := new(DiffConfigReply)
+	err := c.cc.Invoke(ctx, "/configsaver.ConfigSaver/DiffConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configSaverClient) ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsReply, error) {
+	out := new(ListProductsReply)
+	err := c.cc.Invoke(ctx, "/configsaver.ConfigSaver/ListProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configSaverClient) PromoteConfig(ctx context.Context, in *PromoteConfigRequest, opts ...grpc.CallOption) (*PromoteConfigReply, error) {
+	out := new(PromoteConfigReply)
+	err := c.cc.Invoke(ctx, "/configsaver.ConfigSaver/PromoteConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configSaverClient) WatchConfig(ctx context.Context, in *WatchConfigRequest, opts ...grpc.CallOption) (ConfigSaver_WatchConfigClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ConfigSaver_ServiceDesc.Streams[0], "/configsaver.ConfigSaver/WatchConfig", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configSaverWatchConfigClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ConfigSaver_WatchConfigClient interface {
+	Recv() (*ConfigEvent, error)
+	grpc.ClientStream
+}
+
+type configSaverWatchConfigClient struct {
+	grpc.ClientStream
+}
+
+func (x *configSaverWatchConfigClient) Recv() (*ConfigEvent, error) {
+	m := new(ConfigEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *configSaverClient) PullConfig(ctx context.Context, in *PullConfigRequest, opts ...grpc.CallOption) (*PullConfigReply, error) {
+	out := new(PullConfigReply)
+	err := c.cc.Invoke(ctx, "/configsaver.ConfigSaver/PullConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configSaverClient) GetConfigStream(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (ConfigSaver_GetConfigStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ConfigSaver_ServiceDesc.Streams[1], "/configsaver.ConfigSaver/GetConfigStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configSaverGetConfigStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ConfigSaver_GetConfigStreamClient interface {
+	Recv() (*GetConfigChunk, error)
+	grpc.ClientStream
+}
+
+type configSaverGetConfigStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *configSaverGetConfigStreamClient) Recv() (*GetConfigChunk, error) {
+	m := new(GetConfigChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *configSaverClient) UpdateConfigStream(ctx context.Context, opts ...grpc.CallOption) (ConfigSaver_UpdateConfigStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ConfigSaver_ServiceDesc.Streams[2], "/configsaver.ConfigSaver/UpdateConfigStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configSaverUpdateConfigStreamClient{stream}
+	return x, nil
+}
+
+type ConfigSaver_UpdateConfigStreamClient interface {
+	Send(*UpdateConfigChunk) error
+	CloseAndRecv() (*UpdateConfigReply, error)
+	grpc.ClientStream
+}
+
+type configSaverUpdateConfigStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *configSaverUpdateConfigStreamClient) Send(m *UpdateConfigChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *configSaverUpdateConfigStreamClient) CloseAndRecv() (*UpdateConfigReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UpdateConfigReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *configSaverClient) CompareManifest(ctx context.Context, in *CompareManifestRequest, opts ...grpc.CallOption) (*CompareManifestReply, error) {
+	out := new(CompareManifestReply)
+	err := c.cc.Invoke(ctx, "/configsaver.ConfigSaver/CompareManifest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConfigSaverServer is the server API for ConfigSaver service.
 // All implementations must embed UnimplementedConfigSaverServer
 // for forward compatibility
@@ -62,6 +265,35 @@ type ConfigSaverServer interface {
 	// The server is responsible for determining whether the configuration is valid, has changed
 	// and how to persist it.
 	UpdateConfig(context.Context, *UpdateConfigRequest) (*UpdateConfigReply, error)
+	// Push committed configuration to the upstream git repository.
+	PushConfig(context.Context, *PushConfigRequest) (*PushConfigReply, error)
+	// List the commits that changed a product's configuration, newest first.
+	ListRevisions(context.Context, *ListRevisionsRequest) (*ListRevisionsReply, error)
+	// Restore a product's configuration to a previous revision. The restored configuration is committed
+	// as a new revision.
+	RollbackConfig(context.Context, *RollbackConfigRequest) (*RollbackConfigReply, error)
+	// Compare a product's configuration between two revisions, or between a revision and
+	// configuration uploaded by the client. Nothing is saved.
+	DiffConfig(context.Context, *DiffConfigRequest) (*DiffConfigReply, error)
+	// List the products the server has configuration for.
+	ListProducts(context.Context, *ListProductsRequest) (*ListProductsReply, error)
+	// Copy the configuration of one product profile to another (for example, cdk to prod).
+	PromoteConfig(context.Context, *PromoteConfigRequest) (*PromoteConfigReply, error)
+	// Watch a product's configuration. The server sends an event each time a commit changes files in the
+	// product's configuration directory, until the client cancels the call.
+	WatchConfig(*WatchConfigRequest, ConfigSaver_WatchConfigServer) error
+	// Fetch the upstream git repository, and bring the server's branch up to date with it. Local commits are
+	// rebased onto the upstream branch. Clients watching changed products are notified.
+	PullConfig(context.Context, *PullConfigRequest) (*PullConfigReply, error)
+	// GetConfig for configurations larger than the gRPC message size limit. The server sends a header, then the
+	// tar file in chunks, then a trailer with the checksum.
+	GetConfigStream(*GetConfigRequest, ConfigSaver_GetConfigStreamServer) error
+	// UpdateConfig for configurations larger than the gRPC message size limit. The client sends a header, then the
+	// tar file in chunks, then a trailer with the checksum. The update is only saved if the checksum matches.
+	UpdateConfigStream(ConfigSaver_UpdateConfigStreamServer) error
+	// Compare the client's files, listed with their checksums, with the product's configuration, so the client
+	// only uploads the files the server does not have. Nothing is saved.
+	CompareManifest(context.Context, *CompareManifestRequest) (*CompareManifestReply, error)
 	mustEmbedUnimplementedConfigSaverServer()
 }
 
@@ -75,6 +307,39 @@ func (UnimplementedConfigSaverServer) GetConfig(context.Context, *GetConfigReque
 func (UnimplementedConfigSaverServer) UpdateConfig(context.Context, *UpdateConfigRequest) (*UpdateConfigReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateConfig not implemented")
 }
+func (UnimplementedConfigSaverServer) PushConfig(context.Context, *PushConfigRequest) (*PushConfigReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushConfig not implemented")
+}
+func (UnimplementedConfigSaverServer) ListRevisions(context.Context, *ListRevisionsRequest) (*ListRevisionsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRevisions not implemented")
+}
+func (UnimplementedConfigSaverServer) RollbackConfig(context.Context, *RollbackConfigRequest) (*RollbackConfigReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackConfig not implemented")
+}
+func (UnimplementedConfigSaverServer) DiffConfig(context.Context, *DiffConfigRequest) (*DiffConfigReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffConfig not implemented")
+}
+func (UnimplementedConfigSaverServer) ListProducts(context.Context, *ListProductsRequest) (*ListProductsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
+}
+func (UnimplementedConfigSaverServer) PromoteConfig(context.Context, *PromoteConfigRequest) (*PromoteConfigReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PromoteConfig not implemented")
+}
+func (UnimplementedConfigSaverServer) WatchConfig(*WatchConfigRequest, ConfigSaver_WatchConfigServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchConfig not implemented")
+}
+func (UnimplementedConfigSaverServer) PullConfig(context.Context, *PullConfigRequest) (*PullConfigReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PullConfig not implemented")
+}
+func (UnimplementedConfigSaverServer) GetConfigStream(*GetConfigRequest, ConfigSaver_GetConfigStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetConfigStream not implemented")
+}
+func (UnimplementedConfigSaverServer) UpdateConfigStream(ConfigSaver_UpdateConfigStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpdateConfigStream not implemented")
+}
+func (UnimplementedConfigSaverServer) CompareManifest(context.Context, *CompareManifestRequest) (*CompareManifestReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareManifest not implemented")
+}
 func (UnimplementedConfigSaverServer) mustEmbedUnimplementedConfigSaverServer() {}
 
 // UnsafeConfigSaverServer may be embedded to opt out of forward compatibility for this service.
@@ -124,6 +389,218 @@ func _ConfigSaver_UpdateConfig_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ConfigSaver_PushConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigSaverServer).PushConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/configsaver.ConfigSaver/PushConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigSaverServer).PushConfig(ctx, req.(*PushConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigSaver_ListRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigSaverServer).ListRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/configsaver.ConfigSaver/ListRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigSaverServer).ListRevisions(ctx, req.(*ListRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigSaver_RollbackConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigSaverServer).RollbackConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/configsaver.ConfigSaver/RollbackConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigSaverServer).RollbackConfig(ctx, req.(*RollbackConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigSaver_DiffConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigSaverServer).DiffConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/configsaver.ConfigSaver/DiffConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigSaverServer).DiffConfig(ctx, req.(*DiffConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigSaver_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigSaverServer).ListProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/configsaver.ConfigSaver/ListProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigSaverServer).ListProducts(ctx, req.(*ListProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigSaver_PromoteConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigSaverServer).PromoteConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/configsaver.ConfigSaver/PromoteConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigSaverServer).PromoteConfig(ctx, req.(*PromoteConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigSaver_WatchConfig_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchConfigRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConfigSaverServer).WatchConfig(m, &configSaverWatchConfigServer{stream})
+}
+
+type ConfigSaver_WatchConfigServer interface {
+	Send(*ConfigEvent) error
+	grpc.ServerStream
+}
+
+type configSaverWatchConfigServer struct {
+	grpc.ServerStream
+}
+
+func (x *configSaverWatchConfigServer) Send(m *ConfigEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ConfigSaver_PullConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PullConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigSaverServer).PullConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/configsaver.ConfigSaver/PullConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigSaverServer).PullConfig(ctx, req.(*PullConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigSaver_GetConfigStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetConfigRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConfigSaverServer).GetConfigStream(m, &configSaverGetConfigStreamServer{stream})
+}
+
+type ConfigSaver_GetConfigStreamServer interface {
+	Send(*GetConfigChunk) error
+	grpc.ServerStream
+}
+
+type configSaverGetConfigStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *configSaverGetConfigStreamServer) Send(m *GetConfigChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ConfigSaver_UpdateConfigStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ConfigSaverServer).UpdateConfigStream(&configSaverUpdateConfigStreamServer{stream})
+}
+
+type ConfigSaver_UpdateConfigStreamServer interface {
+	SendAndClose(*UpdateConfigReply) error
+	Recv() (*UpdateConfigChunk, error)
+	grpc.ServerStream
+}
+
+type configSaverUpdateConfigStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *configSaverUpdateConfigStreamServer) SendAndClose(m *UpdateConfigReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *configSaverUpdateConfigStreamServer) Recv() (*UpdateConfigChunk, error) {
+	m := new(UpdateConfigChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _ConfigSaver_CompareManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareManifestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigSaverServer).CompareManifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/configsaver.ConfigSaver/CompareManifest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigSaverServer).CompareManifest(ctx, req.(*CompareManifestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ConfigSaver_ServiceDesc is the grpc.ServiceDesc for ConfigSaver service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -139,7 +616,55 @@ var ConfigSaver_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateConfig",
 			Handler:    _ConfigSaver_UpdateConfig_Handler,
 		},
+		{
+			MethodName: "PushConfig",
+			Handler:    _ConfigSaver_PushConfig_Handler,
+		},
+		{
+			MethodName: "ListRevisions",
+			Handler:    _ConfigSaver_ListRevisions_Handler,
+		},
+		{
+			MethodName: "RollbackConfig",
+			Handler:    _ConfigSaver_RollbackConfig_Handler,
+		},
+		{
+			MethodName: "DiffConfig",
+			Handler:    _ConfigSaver_DiffConfig_Handler,
+		},
+		{
+			MethodName: "ListProducts",
+			Handler:    _ConfigSaver_ListProducts_Handler,
+		},
+		{
+			MethodName: "PromoteConfig",
+			Handler:    _ConfigSaver_PromoteConfig_Handler,
+		},
+		{
+			MethodName: "PullConfig",
+			Handler:    _ConfigSaver_PullConfig_Handler,
+		},
+		{
+			MethodName: "CompareManifest",
+			Handler:    _ConfigSaver_CompareManifest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchConfig",
+			Handler:       _ConfigSaver_WatchConfig_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetConfigStream",
+			Handler:       _ConfigSaver_GetConfigStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UpdateConfigStream",
+			Handler:       _ConfigSaver_UpdateConfigStream_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/configsaver.proto",
 }
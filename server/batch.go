@@ -0,0 +1,246 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	git "github.com/ForgeRock/configsaver/internal/git"
+)
+
+// updateSource is who an update came from. A commit has a single author, so updates from different
+// sources are never committed together.
+type updateSource struct {
+	author  git.Author
+	podName string
+}
+
+// pendingCommit holds a product's updates that are written to the working tree but not committed yet
+type pendingCommit struct {
+	productId string
+	source    updateSource
+	// the latest commit message sent with an update, if any
+	message string
+	started time.Time
+	timer   *time.Timer
+	// the commit ids returned to the updates. They do not include the updates' changes.
+	replies map[string]bool
+}
+
+// batchedCommit is the commit that saved a product's pending updates
+type batchedCommit struct {
+	replies  map[string]bool
+	commitId string
+}
+
+// batchKey is a product path, and the source of updates to it
+type batchKey struct {
+	productPath string
+	source      updateSource
+}
+
+// commitBatcher coalesces the updates made to each product into one commit. The commit is made once no update
+// has arrived for the quiet period, or when the first update reaches the max age. A quiet period of 0 commits
+// each update as it arrives.
+type commitBatcher struct {
+	quiet, maxAge time.Duration
+	lock          sync.Mutex
+	// by product path
+	pending map[string]*pendingCommit
+	// the last commit of each source's pending updates to a product. Updates from other sources may have been
+	// committed since, so it is kept for each source.
+	committed map[batchKey]*batchedCommit
+}
+
+func newCommitBatcher(quiet, maxAge time.Duration) *commitBatcher {
+	return &commitBatcher{
+		quiet:     quiet,
+		maxAge:    maxAge,
+		pending:   make(map[string]*pendingCommit),
+		committed: make(map[batchKey]*batchedCommit),
+	}
+}
+
+// delay is how long to wait for more updates before committing
+func (b *commitBatcher) delay(p *pendingCommit) time.Duration {
+	d := b.quiet
+	if remaining := b.maxAge - time.Since(p.started); remaining < d {
+		d = remaining
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+// baseFor returns the commit to merge an update against. The client was given a commit id before its earlier
+// updates were committed. Once they are, its base is the commit that saved them, so the client's own changes
+// are not mistaken for changes made on the server.
+func (b *commitBatcher) baseFor(productPath string, source updateSource, base string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	c, ok := b.committed[batchKey{productPath, source}]
+	if ok && c.replies[base] {
+		return c.commitId
+	}
+	return base
+}
+
+// saveUpdate commits an update that has been written to the working tree, or adds it to the product's pending
+// commit. It returns the commit the client should base its next update on. The caller must hold the product lock
+func (s *ConfigServer) saveUpdate(productPath, productId, message string, source updateSource) (string, error) {
+	b := s.batches
+	if b.quiet <= 0 {
+		return s.commitUpdate(productPath, productId, message, source)
+	}
+	// Nothing else can commit this product's files until the pending commit is made, so HEAD has the same
+	// configuration the client's next update will be merged against.
+	head, err := s.headCommitId()
+	if err != nil {
+		return "", err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	p, ok := b.pending[productPath]
+	if !ok {
+		p = &pendingCommit{productId: productId, source: source, started: time.Now(), replies: make(map[string]bool)}
+		p.timer = time.AfterFunc(b.delay(p), func() { s.commitWhenDue(productPath, p) })
+		b.pending[productPath] = p
+	} else {
+		p.timer.Reset(b.delay(p))
+	}
+	if message != "" {
+		p.message = message
+	}
+	p.replies[head] = true
+	return head, nil
+}
+
+// commitUpdate commits the changes under the product path. The caller must hold the product lock
+func (s *ConfigServer) commitUpdate(productPath, productId, message string, source updateSource) (string, error) {
+	subject := message
+	if subject == "" {
+		subject = fmt.Sprintf("Update %s configuration", productId)
+	}
+	return s.commits.run(func() (string, error) {
+		summary, err := s.changeSummary(productPath)
+		if err != nil {
+			return "", err
+		}
+		message := commitMessage(subject, summary,
+			trailer{"Product", productId},
+			trailer{"Source-Pod", source.podName})
+		author := source.author
+		return s.commitChanges(message, &author, productPath)
+	})
+}
+
+// flushPending commits the product's pending updates, if there are any. The caller must hold the product lock
+func (s *ConfigServer) flushPending(productPath string) error {
+	b := s.batches
+	b.lock.Lock()
+	p, ok := b.pending[productPath]
+	delete(b.pending, productPath)
+	b.lock.Unlock()
+	if !ok {
+		return nil
+	}
+	p.timer.Stop()
+
+	commitId, err := s.commitUpdate(productPath, p.productId, p.message, p.source)
+	if err != nil {
+		// The changes stay in the working tree, and are committed with the product's next update
+		return err
+	}
+	b.lock.Lock()
+	b.committed[batchKey{productPath, p.source}] = &batchedCommit{replies: p.replies, commitId: commitId}
+	b.lock.Unlock()
+	return nil
+}
+
+// flushOtherSource commits the product's pending updates if they came from a different source.
+// The caller must hold the product lock
+func (s *ConfigServer) flushOtherSource(productPath string, source updateSource) error {
+	b := s.batches
+	b.lock.Lock()
+	p, ok := b.pending[productPath]
+	b.lock.Unlock()
+	if !ok || p.source == source {
+		return nil
+	}
+	return s.flushPending(productPath)
+}
+
+// flushProduct locks the product and commits its pending updates
+func (s *ConfigServer) flushProduct(productPath string) error {
+	unlock := s.products.lock(productPath)
+	defer unlock()
+	return s.flushPending(productPath)
+}
+
+// flushAll commits the pending updates of every product, for example before a push or a pull.
+// Errors are logged.
+func (s *ConfigServer) flushAll() {
+	for _, path := range s.batches.pendingPaths() {
+		if err := s.flushProduct(path); err != nil {
+			log.Printf("error committing pending updates to %s: %v", path, err)
+		}
+	}
+}
+
+// flushAllLocked commits the pending updates of every product. The caller must hold every product lock, from
+// lockAll. Errors are logged.
+func (s *ConfigServer) flushAllLocked() {
+	for _, path := range s.batches.pendingPaths() {
+		if err := s.flushPending(path); err != nil {
+			log.Printf("error committing pending updates to %s: %v", path, err)
+		}
+	}
+}
+
+// pendingPaths returns the product paths that have pending updates
+func (b *commitBatcher) pendingPaths() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	paths := make([]string, 0, len(b.pending))
+	for path := range b.pending {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// commitWhenDue is called by the pending commit's timer. The commit may already have been made.
+func (s *ConfigServer) commitWhenDue(productPath string, p *pendingCommit) {
+	unlock := s.products.lock(productPath)
+	defer unlock()
+	b := s.batches
+	b.lock.Lock()
+	current := b.pending[productPath]
+	b.lock.Unlock()
+	if current != p {
+		return
+	}
+	if err := s.flushPending(productPath); err != nil {
+		log.Printf("error committing pending updates to %s: %v", productPath, err)
+	}
+}
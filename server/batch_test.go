@@ -0,0 +1,73 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchSourcesCommitSeparately(t *testing.T) {
+	// nothing is committed by the timer while the test runs
+	s := newTestServer(t, time.Minute)
+	_, initial := getAm(t, s, "")
+	updateAm(t, s, "am-0", initial, map[string][]byte{"am.json": []byte(`{"a": 2, "b": 1}`)})
+	updateAm(t, s, "am-0", initial, map[string][]byte{"am.json": []byte(`{"a": 3, "b": 1}`)})
+	updateAm(t, s, "am-1", initial, map[string][]byte{"other.json": []byte(`{}`)})
+	// reading the configuration commits the pending update
+	getAm(t, s, "")
+
+	revisions, err := s.GitRepo.Log(amPath, "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// am-0's two updates are one commit, and am-1's update is another, on top of the initial commit
+	if len(revisions) != 3 {
+		t.Fatalf("Log() = %d commits, want 3", len(revisions))
+	}
+	for i, want := range []struct{ pod, file string }{{"am-1", "other.json"}, {"am-0", "am.json"}} {
+		r := revisions[i]
+		if !strings.Contains(r.Message, "Source-Pod: "+want.pod) {
+			t.Errorf("commit %s message %q, want it from %s", r.CommitId, r.Message, want.pod)
+		}
+		if len(r.Files) != 1 || r.Files[0] != want.file {
+			t.Errorf("commit %s changed %v, want %s", r.CommitId, r.Files, want.file)
+		}
+	}
+}
+
+func TestBatchReadAfterWrite(t *testing.T) {
+	s := newTestServer(t, time.Minute)
+	_, initial := getAm(t, s, "")
+	updateAm(t, s, "am-0", initial, map[string][]byte{"am.json": []byte(`{"a": 2, "b": 1}`)})
+
+	files, commitId := getAm(t, s, "")
+	if commitId == initial {
+		t.Errorf("GetConfig() commit = %s, want the batched update committed", commitId)
+	}
+	if got := string(files["am.json"]); got != `{"a": 2, "b": 1}` {
+		t.Errorf("am.json = %s, want the batched update", got)
+	}
+	// the commit returned has the update
+	files, _ = getAm(t, s, commitId)
+	if got := string(files["am.json"]); got != `{"a": 2, "b": 1}` {
+		t.Errorf("am.json at %s = %s, want the batched update", commitId, got)
+	}
+}
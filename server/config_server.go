@@ -21,59 +21,167 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
 
 	f "github.com/ForgeRock/configsaver/internal/fileutils"
 	git "github.com/ForgeRock/configsaver/internal/git"
 
 	pb "github.com/ForgeRock/configsaver/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
 	port = ":50051"
+
+	// Push modes, set with GIT_PUSH_MODE
+	// Only push when a client calls PushConfig
+	pushOnDemand = "none"
+	// Push after every commit
+	pushOnCommit = "commit"
+	// Push any new commits every GIT_PUSH_INTERVAL seconds
+	pushOnInterval = "interval"
+
+	// How often to check if the configuration file changed
+	configPollInterval = 10 * time.Second
+
+	// Page size limits for ListRevisions
+	defaultPageSize = 50
+	maxPageSize     = 500
 )
 
+// The compression the server reads and writes tar files with, most preferred first
+var acceptedCompression = []pb.Compression{pb.Compression_ZSTD, pb.Compression_GZIP, pb.Compression_UNCOMPRESSED}
+
 // config saver server context + config
 type ConfigServer struct {
 	// The top of directory where we serve config from.
 	RootDirectory string
-	// Map of the relative paths to the product configuration.
-	// Example:  am: docker/am/configs/cdk
-	ProductPath map[string]string
+	// Where the product configuration is in the repo. Reloaded if the configuration file changes.
+	serverConfig *ServerConfig
+	configLock   sync.RWMutex
 	*f.FileUtil
 	*git.GitRepo
+	// When to push commits to the upstream repo. One of the push* modes
+	PushMode string
+	// serializes access to the git repo, including reading the history, as the repository objects are not safe
+	// to use at the same time as a commit or pull changes the repo.
+	gitLock sync.Mutex
+	// commits changes, one at a time
+	commits *commitQueue
+	// coalesces each product's updates into fewer commits
+	batches *commitBatcher
+	// serializes changes to each product's files
+	products productLocks
+	// open WatchConfig calls, told about each commit
+	watchers watchers
+	// checksums of the blobs compared with client manifests
+	blobHashes blobHashes
+
 	pb.UnimplementedConfigSaverServer // for gRPC
 }
 
 var config *ConfigServer
 
+// Clients ping every 10 seconds to keep connections (and WatchConfig calls) open. Allow them, instead
+// of closing the connection for pinging too often.
+var kaep = keepalive.EnforcementPolicy{
+	MinTime:             5 * time.Second,
+	PermitWithoutStream: true,
+}
+
 func main() {
 	rootDir := f.GetEnvOrDefault("CONFIG_DIR", "/tmp/frconfig")
 
+	// The products and where to find them in the repo
+	serverConfig := defaultServerConfig()
+	configFile := os.Getenv("CONFIG_FILE")
+	if configFile != "" {
+		var err error
+		if serverConfig, err = loadServerConfig(configFile); err != nil {
+			log.Fatalf("failed to load configuration: %v", err)
+		}
+	}
+
+	// The branch to save configuration to. If it does not exist upstream it is created from the base branch or tag.
+	branch := f.GetEnvOrDefault("GIT_BRANCH", "master")
+	baseRef := f.GetEnvOrDefault("GIT_BASE_BRANCH", "master")
+
 	// This will look for GIT_REPO and GIT_SSH_PATH environment variables.
-	gitRepo, err := git.OpenGitRepo(rootDir, "master")
+	gitRepo, err := git.OpenGitRepo(rootDir, branch, baseRef)
 	if err != nil {
 		log.Fatalf("failed to open git repo: %v", err)
 	}
+	// Optionally publish a newly created branch now, instead of waiting for the first push.
+	if f.GetEnvOrDefault("GIT_PUBLISH_BRANCH", "false") == "true" {
+		needsPush, err := gitRepo.NeedsPush()
+		if err == nil && needsPush {
+			err = gitRepo.Push()
+		}
+		if err != nil {
+			log.Fatalf("failed to publish branch %s: %v", branch, err)
+		}
+	}
 
 	config = &ConfigServer{
 		RootDirectory: rootDir,
-		ProductPath: map[string]string{
-			"am":  "docker/am/config-profiles/cdk",
-			"idm": "docker/idm/config-profiles/cdk",
-		},
-		FileUtil: f.NewFileUtil(rootDir),
-		GitRepo:  gitRepo,
+		FileUtil:      f.NewFileUtil(rootDir),
+		GitRepo:       gitRepo,
+		PushMode:      f.GetEnvOrDefault("GIT_PUSH_MODE", pushOnDemand),
+	}
+	config.commits = newCommitQueue(&config.gitLock)
+
+	// Optionally coalesce a product's updates into one commit, made after GIT_COMMIT_QUIET seconds without an
+	// update, or GIT_COMMIT_MAX_AGE seconds after the first update.
+	quietSeconds, err := strconv.Atoi(f.GetEnvOrDefault("GIT_COMMIT_QUIET", "0"))
+	if err != nil || quietSeconds < 0 {
+		log.Fatalf("Invalid GIT_COMMIT_QUIET: %v", err)
+	}
+	maxAgeSeconds, err := strconv.Atoi(f.GetEnvOrDefault("GIT_COMMIT_MAX_AGE", "60"))
+	if err != nil || maxAgeSeconds < 1 {
+		log.Fatalf("Invalid GIT_COMMIT_MAX_AGE: %v", err)
+	}
+	config.batches = newCommitBatcher(time.Duration(quietSeconds)*time.Second, time.Duration(maxAgeSeconds)*time.Second)
+	config.setServerConfig(serverConfig)
+	if configFile != "" {
+		go config.watchServerConfig(configFile, configPollInterval)
+	}
+
+	switch config.PushMode {
+	case pushOnDemand, pushOnCommit:
+	case pushOnInterval:
+		seconds, err := strconv.Atoi(f.GetEnvOrDefault("GIT_PUSH_INTERVAL", "300"))
+		if err != nil || seconds < 1 {
+			log.Fatalf("Invalid GIT_PUSH_INTERVAL: %v", err)
+		}
+		go config.pushLoop(time.Duration(seconds) * time.Second)
+	default:
+		log.Fatalf("Invalid GIT_PUSH_MODE %s. Must be one of %s, %s or %s", config.PushMode, pushOnDemand, pushOnCommit, pushOnInterval)
+	}
+
+	// Optionally pick up changes made to the branch upstream, for example a merged pull request.
+	pullSeconds, err := strconv.Atoi(f.GetEnvOrDefault("GIT_PULL_INTERVAL", "0"))
+	if err != nil || pullSeconds < 0 {
+		log.Fatalf("Invalid GIT_PULL_INTERVAL: %v", err)
+	}
+	if pullSeconds > 0 {
+		go config.pullLoop(time.Duration(pullSeconds) * time.Second)
 	}
 
 	lis, err := net.Listen("tcp", port)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	s := grpc.NewServer()
+	s := grpc.NewServer(grpc.KeepaliveEnforcementPolicy(kaep))
 	pb.RegisterConfigSaverServer(s, config)
 	log.Printf("server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
@@ -82,39 +190,600 @@ func main() {
 }
 
 // GetConfig returns the entire config for a given product. Returns to the caller as tar file
+// If a commit id (a commit, branch or tag) is provided the config is read from that revision in git,
+// otherwise the current working tree is returned. If the client sends a manifest of its files, only the files that
+// differ are returned.
 func (s *ConfigServer) GetConfig(ctx context.Context, in *pb.GetConfigRequest) (*pb.GetConfigReply, error) {
 
-	log.Printf("GetConfig product: %s commit: %s", in.ProductId, in.CommitId)
-	bytes, err := s.FileUtil.GetAllConfiguration(config.ProductPath[in.ProductId])
+	log.Printf("GetConfig product: %s profile: %s commit: %s", in.ProductId, in.Profile, in.CommitId)
+	commitId, files, removed, err := s.readConfig(in)
 	if err != nil {
-		return &pb.GetConfigReply{Status: 1, ErrorMessage: err.Error()}, err
+		return nil, err
 	}
-	fmt.Printf("sending tar file with %d bytes", len(bytes))
-	return &pb.GetConfigReply{Status: 0, ErrorMessage: "ok", ConfigTar: bytes}, nil
+	compression := chooseCompression(in.AcceptCompression)
+	bytes, err := f.CreateTarBufferFromFiles(files, f.Compression(compression))
+	if err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+	fmt.Printf("sending %s tar file with %d bytes from commit %s", f.Compression(compression), len(bytes), commitId)
+	return &pb.GetConfigReply{
+		CommitId:            commitId,
+		ConfigTar:           bytes,
+		Compression:         compression,
+		AcceptedCompression: acceptedCompression,
+		DeletedFiles:        removed,
+	}, nil
+}
+
+// Older clients send master as the commit when they mean the server's current configuration
+const legacyCommitId = "master"
+
+// requestedCommit returns the commit a request asks for. An empty commit is the current configuration, the
+// HEAD of the branch the server saves to. Older clients ask for it as master, which is not that branch.
+func requestedCommit(commitId string) string {
+	if commitId == legacyCommitId {
+		return ""
+	}
+	return commitId
+}
+
+// readConfig returns the product files for a GetConfig request, the files in the request's manifest that were
+// removed, and the commit they were read from. Errors are returned as gRPC status errors.
+func (s *ConfigServer) readConfig(in *pb.GetConfigRequest) (string, map[string][]byte, []string, error) {
+	in.CommitId = requestedCommit(in.CommitId)
+	product, productPath, err := s.resolveProduct(in.ProductId, in.Profile)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(in.Manifest) > 0 {
+		commitId, files, removed, err := s.readManifestConfig(in, product, productPath)
+		if err != nil {
+			return "", nil, nil, statusError(err, in.ProductId)
+		}
+		return commitId, files, removed, nil
+	}
+
+	var files map[string][]byte
+	var commitId string
+	if in.CommitId == "" {
+		// Commit any pending updates, so the files returned are the files in the commit
+		if err = s.flushProduct(productPath); err != nil {
+			return "", nil, nil, statusError(err, in.ProductId)
+		}
+		// No update to the product can be part way through while we read the files
+		unlock := s.products.rlock(productPath)
+		defer unlock()
+		if commitId, err = s.headCommitId(); err != nil {
+			return "", nil, nil, statusError(err, in.ProductId)
+		}
+		files, err = s.FileUtil.ReadFiles(productPath)
+	} else {
+		s.gitLock.Lock()
+		commitId, files, err = s.GitRepo.TreeFiles(in.CommitId, productPath)
+		s.gitLock.Unlock()
+	}
+	if err != nil {
+		return "", nil, nil, statusError(err, in.ProductId)
+	}
+	files, _ = product.filter(files, nil)
+	return commitId, files, nil, nil
 }
 
 // UpdateConfig is called by the client to pass along config updates to be saved.
+// If the client sends the commit its configuration is based on, files that were also changed on the server since
+// that commit are merged. Changes that can not be merged are rejected as conflicts.
 func (s *ConfigServer) UpdateConfig(ctx context.Context, in *pb.UpdateConfigRequest) (*pb.UpdateConfigReply, error) {
-	log.Printf("UpdateConfig product: %s commit: %s", in.ProductId, in.CommitId)
+	log.Printf("UpdateConfig product: %s profile: %s commit: %s", in.ProductId, in.Profile, in.CommitId)
+
+	// Read the tar file containing the changes, and check them before we write anything
+	files, err := f.ReadTarBuffer(in.ConfigTar)
+	if err != nil {
+		log.Printf("could not read tar buffer: %v\n", err)
+		return nil, statusError(err, in.ProductId)
+	}
+	return s.saveConfig(in, files)
+}
+
+// saveConfig saves the files of an update, read from its tar file, and the files it deletes.
+// Errors are returned as gRPC status errors.
+func (s *ConfigServer) saveConfig(in *pb.UpdateConfigRequest, files map[string][]byte) (*pb.UpdateConfigReply, error) {
+	product, productPath, err := s.resolveProduct(in.ProductId, in.Profile)
+	if err != nil {
+		return nil, err
+	}
 
-	// Unpack the tar file containing the changes
-	err := s.FileUtil.UnpackTarBuffer(in.ConfigTar, config.ProductPath[in.ProductId])
+	deletedFiles, err := f.CheckPaths(in.DeletedFiles)
 	if err != nil {
-		log.Printf("could not unpack tar buffer: %v\n", err)
-		return &pb.UpdateConfigReply{Status: 1, ErrorMessage: err.Error()}, err
+		return nil, statusError(err, in.ProductId)
+	}
+	files, deletedFiles = product.filter(files, deletedFiles)
+	if err = product.validate(files); err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+
+	unlock := s.products.lock(productPath)
+	defer unlock()
+
+	source := updateSource{author: git.Author{Name: in.AuthorName, Email: in.AuthorEmail}, podName: in.PodName}
+	if source.author != (git.Author{}) && !source.author.Valid() {
+		// git can not sign a commit with it, so the server is the author
+		log.Printf("ignoring author %q <%q> of %s update: a name and an email without < or > are needed", in.AuthorName, in.AuthorEmail, in.ProductId)
+		source.author = git.Author{}
+	}
+	if err = s.flushOtherSource(productPath, source); err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+
+	// Merge with changes made on the server since the client's copy was read.
+	if base := s.batches.baseFor(productPath, source, requestedCommit(in.CommitId)); base != "" {
+		s.gitLock.Lock()
+		files, err = s.mergeUpdate(base, productPath, files, deletedFiles)
+		s.gitLock.Unlock()
+		if err != nil {
+			return nil, statusError(err, in.ProductId)
+		}
+	}
+
+	if err = s.FileUtil.WriteFiles(files, productPath); err != nil {
+		return nil, statusError(err, in.ProductId)
 	}
 	// new see if the client removed any files.
-	if len(in.DeletedFiles) > 0 {
-		err = config.FileUtil.DeleteFiles(in.DeletedFiles, config.ProductPath[in.ProductId])
+	if len(deletedFiles) > 0 {
+		err = s.FileUtil.DeleteFiles(deletedFiles, productPath)
 		if err != nil {
-			return &pb.UpdateConfigReply{Status: 1, ErrorMessage: err.Error()}, err
+			return nil, statusError(err, in.ProductId)
 		}
 	}
 	// Update git...
-	if err = s.GitRepo.GitStatusAndCommit(); err != nil {
+	commitId, err := s.saveUpdate(productPath, in.ProductId, in.Message, source)
+	if err != nil {
 		fmt.Printf("error commiting changes to git %v", err)
-		return &pb.UpdateConfigReply{Status: 1, ErrorMessage: err.Error()}, err
+		return nil, statusError(err, in.ProductId)
+	}
+
+	return &pb.UpdateConfigReply{CommitId: commitId, AcceptedCompression: acceptedCompression}, nil
+}
+
+// RollbackConfig restores the product configuration to a previous revision, and commits the result.
+func (s *ConfigServer) RollbackConfig(ctx context.Context, in *pb.RollbackConfigRequest) (*pb.RollbackConfigReply, error) {
+	log.Printf("RollbackConfig product: %s commit: %s", in.ProductId, in.CommitId)
+	_, productPath, err := s.resolveProduct(in.ProductId, in.Profile)
+	if err != nil {
+		return nil, err
 	}
 
-	return &pb.UpdateConfigReply{Status: 0, ErrorMessage: "ok"}, nil
+	unlock := s.products.lock(productPath)
+	defer unlock()
+	// The rollback replaces the files in the working tree. Keep any pending updates in the history.
+	if err = s.flushPending(productPath); err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+
+	commitId, err := s.commits.run(func() (string, error) {
+		target, err := s.GitRepo.RestorePath(in.CommitId, productPath)
+		if err != nil {
+			return "", err
+		}
+		message := fmt.Sprintf("Rollback %s configuration to %.8s\n\nRestored %s to commit %s", in.ProductId, target, productPath, target)
+		return s.commitChanges(message, nil, productPath)
+	})
+	if err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+
+	return &pb.RollbackConfigReply{CommitId: commitId}, nil
+}
+
+// PushConfig pushes any commits to the upstream repository on demand.
+func (s *ConfigServer) PushConfig(ctx context.Context, in *pb.PushConfigRequest) (*pb.PushConfigReply, error) {
+	log.Printf("PushConfig")
+	s.flushAll()
+	s.gitLock.Lock()
+	defer s.gitLock.Unlock()
+	if err := s.GitRepo.Push(); err != nil {
+		if errors.Is(err, git.ErrPushRejected) {
+			return nil, statusError(err, "")
+		}
+		// Most likely a network or authentication problem with the upstream repo
+		return nil, newStatusError(codes.Unavailable, reasonPushFailed, err.Error(), "")
+	}
+	commitId, err := s.GitRepo.HeadCommitId()
+	if err != nil {
+		return nil, statusError(err, "")
+	}
+	return &pb.PushConfigReply{CommitId: commitId}, nil
+}
+
+// PullConfig fetches the upstream repository, and fast forwards or rebases the branch onto it on demand.
+func (s *ConfigServer) PullConfig(ctx context.Context, in *pb.PullConfigRequest) (*pb.PullConfigReply, error) {
+	log.Printf("PullConfig")
+	before, after, err := s.pull()
+	if err != nil {
+		if errors.Is(err, git.ErrConflict) {
+			return nil, statusError(err, "")
+		}
+		// Most likely a network or authentication problem with the upstream repo
+		return nil, newStatusError(codes.Unavailable, reasonPullFailed, err.Error(), "")
+	}
+	return &pb.PullConfigReply{PreviousCommitId: before, CommitId: after}, nil
+}
+
+// ListRevisions returns a page of the commits that changed the product configuration, newest first.
+func (s *ConfigServer) ListRevisions(ctx context.Context, in *pb.ListRevisionsRequest) (*pb.ListRevisionsReply, error) {
+	log.Printf("ListRevisions product: %s page_size: %d page_token: %s", in.ProductId, in.PageSize, in.PageToken)
+
+	pageSize := int(in.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	_, productPath, err := s.resolveProduct(in.ProductId, in.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// The repository objects are not safe to use while a commit or pull changes the repo. A page is a bounded walk.
+	s.gitLock.Lock()
+	revisions, err := s.GitRepo.Log(productPath, "", in.PageToken, pageSize)
+	s.gitLock.Unlock()
+	if err != nil {
+		if in.PageToken != "" && errors.Is(err, git.ErrRevisionNotFound) {
+			return nil, newStatusError(codes.InvalidArgument, reasonInvalidPageToken, err.Error(), in.ProductId)
+		}
+		return nil, statusError(err, in.ProductId)
+	}
+
+	reply := &pb.ListRevisionsReply{}
+	for _, r := range revisions {
+		reply.Revisions = append(reply.Revisions, &pb.Revision{
+			CommitId:    r.CommitId,
+			AuthorName:  r.AuthorName,
+			AuthorEmail: r.AuthorEmail,
+			Timestamp:   timestamppb.New(r.When),
+			Message:     r.Message,
+			Files:       r.Files,
+		})
+	}
+	// A full page means there may be more revisions. The token is the last commit we returned.
+	if len(revisions) == pageSize {
+		reply.NextPageToken = revisions[len(revisions)-1].CommitId
+	}
+	return reply, nil
+}
+
+// DiffConfig compares the product configuration between two revisions, or between a revision and
+// changes uploaded by the client. Nothing is committed.
+func (s *ConfigServer) DiffConfig(ctx context.Context, in *pb.DiffConfigRequest) (*pb.DiffConfigReply, error) {
+	log.Printf("DiffConfig product: %s from: %s to: %s", in.ProductId, in.FromCommitId, in.ToCommitId)
+	product, productPath, err := s.resolveProduct(in.ProductId, in.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	from := in.FromCommitId
+	if from == "" {
+		from = "HEAD"
+	}
+
+	reply := &pb.DiffConfigReply{}
+	var diffs []git.FileDiff
+	if len(in.ConfigTar) > 0 || len(in.DeletedFiles) > 0 {
+		// The archive is read and checked before the git lock is taken, so commits do not wait for it
+		var files map[string][]byte
+		if files, err = f.ReadTarBuffer(in.ConfigTar); err != nil {
+			return nil, statusError(err, in.ProductId)
+		}
+		var deletedFiles []string
+		if deletedFiles, err = f.CheckPaths(in.DeletedFiles); err != nil {
+			return nil, statusError(err, in.ProductId)
+		}
+		// compare what would be saved
+		files, deletedFiles = product.filter(files, deletedFiles)
+		s.gitLock.Lock()
+		diffs, reply.FromCommitId, err = s.GitRepo.DiffFiles(from, productPath, files, deletedFiles)
+		s.gitLock.Unlock()
+	} else {
+		to := in.ToCommitId
+		if to == "" {
+			to = "HEAD"
+		}
+		s.gitLock.Lock()
+		diffs, reply.FromCommitId, reply.ToCommitId, err = s.GitRepo.DiffRevisions(from, to, productPath)
+		s.gitLock.Unlock()
+	}
+	if err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+
+	reply.Files, reply.AddedFiles, reply.ModifiedFiles, reply.DeletedFiles = diffSummary(diffs)
+	return reply, nil
+}
+
+// PromoteConfig copies the configuration of one product profile to another (for example, cdk to prod), and
+// commits the result. If files are listed only those files are copied, otherwise the target profile is
+// replaced with the source profile. A dry run returns the changes without saving them.
+func (s *ConfigServer) PromoteConfig(ctx context.Context, in *pb.PromoteConfigRequest) (*pb.PromoteConfigReply, error) {
+	log.Printf("PromoteConfig product: %s from: %s to: %s files: %v dry_run: %t", in.ProductId, in.FromProfile, in.ToProfile, in.Files, in.DryRun)
+	if in.FromProfile == "" || in.ToProfile == "" || in.FromProfile == in.ToProfile {
+		return nil, newStatusError(codes.InvalidArgument, reasonInvalidProfile, "from_profile and to_profile must be set to different profiles", in.ProductId)
+	}
+	product, fromPath, err := s.resolveProduct(in.ProductId, in.FromProfile)
+	if err != nil {
+		return nil, err
+	}
+	_, toPath, err := s.resolveProduct(in.ProductId, in.ToProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	// The source profile is read from HEAD, so commit its pending updates first
+	if err = s.flushProduct(fromPath); err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+	unlock := s.products.lock(toPath)
+	defer unlock()
+	if err = s.flushPending(toPath); err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+
+	s.gitLock.Lock()
+	reply, files, deletedFiles, err := s.promotion(in, product, fromPath, toPath)
+	s.gitLock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if in.DryRun || len(reply.Files) == 0 {
+		return reply, nil
+	}
+
+	if err = s.FileUtil.WriteFiles(files, toPath); err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+	if err = s.FileUtil.DeleteFiles(deletedFiles, toPath); err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+	message := fmt.Sprintf("Promote %s configuration from %s to %s\n\nCopied %s at commit %s to %s",
+		in.ProductId, in.FromProfile, in.ToProfile, fromPath, reply.SourceCommitId, toPath)
+	reply.CommitId, err = s.commits.run(func() (string, error) {
+		return s.commitChanges(message, nil, toPath)
+	})
+	if err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+	return reply, nil
+}
+
+// promotion works out the files to copy to the target profile and the files to delete from it, and the
+// changes that makes. Errors are returned as gRPC status errors. The caller must hold the gitLock
+func (s *ConfigServer) promotion(in *pb.PromoteConfigRequest, product *ProductConfig, fromPath, toPath string) (*pb.PromoteConfigReply, map[string][]byte, []string, error) {
+	sourceCommitId, sourceFiles, err := s.GitRepo.TreeFiles("HEAD", fromPath)
+	if err != nil {
+		return nil, nil, nil, statusError(err, in.ProductId)
+	}
+	sourceFiles, _ = product.filter(sourceFiles, nil)
+
+	files := sourceFiles
+	deletedFiles := make([]string, 0)
+	if len(in.Files) > 0 {
+		files = make(map[string][]byte)
+		for _, name := range in.Files {
+			data, ok := sourceFiles[name]
+			if !ok {
+				return nil, nil, nil, newStatusError(codes.InvalidArgument, reasonPathNotFound, fmt.Sprintf("%s is not in profile %s", name, in.FromProfile), in.ProductId)
+			}
+			files[name] = data
+		}
+	} else {
+		// Remove files that are not in the source profile. The target profile may not exist yet.
+		_, targetFiles, err := s.GitRepo.TreeFiles("HEAD", toPath)
+		if err != nil && !errors.Is(err, git.ErrPathNotFound) {
+			return nil, nil, nil, statusError(err, in.ProductId)
+		}
+		for name := range targetFiles {
+			if _, ok := sourceFiles[name]; !ok && !product.ignored(name) {
+				deletedFiles = append(deletedFiles, name)
+			}
+		}
+	}
+
+	diffs, _, err := s.GitRepo.DiffFiles("HEAD", toPath, files, deletedFiles)
+	if err != nil {
+		return nil, nil, nil, statusError(err, in.ProductId)
+	}
+	reply := &pb.PromoteConfigReply{SourceCommitId: sourceCommitId}
+	reply.Files, reply.AddedFiles, reply.ModifiedFiles, reply.DeletedFiles = diffSummary(diffs)
+	return reply, files, deletedFiles, nil
+}
+
+// chooseCompression returns the compression to send a tar file with: the first the client accepts that
+// the server supports
+func chooseCompression(accept []pb.Compression) pb.Compression {
+	for _, c := range accept {
+		for _, supported := range acceptedCompression {
+			if c == supported {
+				return c
+			}
+		}
+	}
+	return pb.Compression_UNCOMPRESSED
+}
+
+// diffSummary converts file differences to the protocol messages, and lists the added, modified and deleted files
+func diffSummary(diffs []git.FileDiff) (files []*pb.FileDiff, added, modified, deleted []string) {
+	for _, d := range diffs {
+		fileDiff := &pb.FileDiff{Path: d.Path, Patch: d.Patch}
+		switch d.Change {
+		case git.Added:
+			fileDiff.Change = pb.FileDiff_ADDED
+			added = append(added, d.Path)
+		case git.Deleted:
+			fileDiff.Change = pb.FileDiff_DELETED
+			deleted = append(deleted, d.Path)
+		default:
+			fileDiff.Change = pb.FileDiff_MODIFIED
+			modified = append(modified, d.Path)
+		}
+		files = append(files, fileDiff)
+	}
+	return files, added, modified, deleted
+}
+
+// ListProducts returns the products the server has configuration for
+func (s *ConfigServer) ListProducts(ctx context.Context, in *pb.ListProductsRequest) (*pb.ListProductsReply, error) {
+	log.Printf("ListProducts")
+	cfg := s.getServerConfig()
+
+	// The last changes are cached, so the walks only cover the commits made since the last call
+	s.gitLock.Lock()
+	defer s.gitLock.Unlock()
+	head, err := s.GitRepo.HeadCommitId()
+	if err != nil {
+		return nil, statusError(err, "")
+	}
+
+	reply := &pb.ListProductsReply{}
+	for _, id := range cfg.productIds() {
+		p := cfg.Products[id]
+		product := &pb.Product{ProductId: id, Path: p.configPath(cfg.defaultProfile(p)), Profiles: p.Profiles}
+		if len(p.Profiles) > 0 {
+			product.DefaultProfile = cfg.defaultProfile(p)
+		}
+		// The current revision is the last commit that changed the product configuration
+		commitId, err := s.GitRepo.LastChange(head, product.Path)
+		if err != nil {
+			return nil, statusError(err, id)
+		}
+		product.CommitId = commitId
+		reply.Products = append(reply.Products, product)
+	}
+	return reply, nil
+}
+
+// resolveProduct returns the product configuration and the relative path to the product's configuration
+// directory for the profile. An empty profile selects the default profile. Unknown products return a NotFound
+// error, and profiles that are not allowed an InvalidArgument error, so we never read or write outside of a
+// product's directory.
+func (s *ConfigServer) resolveProduct(productId, profile string) (*ProductConfig, string, error) {
+	cfg := s.getServerConfig()
+	product, ok := cfg.Products[productId]
+	if !ok {
+		return nil, "", newStatusError(codes.NotFound, reasonUnknownProduct, fmt.Sprintf("unknown product %q", productId), productId)
+	}
+	if profile == "" {
+		profile = cfg.defaultProfile(product)
+	} else if !contains(product.Profiles, profile) {
+		return nil, "", newStatusError(codes.InvalidArgument, reasonProfileNotAllowed,
+			fmt.Sprintf("profile %q is not allowed for product %s. Allowed profiles: %v", profile, productId, product.Profiles), productId)
+	}
+	return product, product.configPath(profile), nil
+}
+
+// getServerConfig returns the current server configuration. The configuration is replaced, never
+// modified, when the configuration file is reloaded.
+func (s *ConfigServer) getServerConfig() *ServerConfig {
+	s.configLock.RLock()
+	defer s.configLock.RUnlock()
+	return s.serverConfig
+}
+
+func (s *ConfigServer) setServerConfig(cfg *ServerConfig) {
+	s.configLock.Lock()
+	defer s.configLock.Unlock()
+	s.serverConfig = cfg
+	log.Printf("serving configuration for products %v", cfg.productIds())
+}
+
+// headCommitId returns the current commit
+func (s *ConfigServer) headCommitId() (string, error) {
+	s.gitLock.Lock()
+	defer s.gitLock.Unlock()
+	return s.GitRepo.HeadCommitId()
+}
+
+// commitChanges commits any changes in the working tree under the paths, then pushes and notifies watchers as
+// needed. The author may be nil. Use the commit queue to run it.
+func (s *ConfigServer) commitChanges(message string, author *git.Author, paths ...string) (string, error) {
+	previous, err := s.GitRepo.HeadCommitId()
+	if err != nil {
+		return "", err
+	}
+	commitId, err := s.GitRepo.GitStatusAndCommit(message, author, paths...)
+	if err != nil {
+		return "", err
+	}
+	if commitId != previous {
+		s.afterCommit(previous, commitId)
+	}
+	return commitId, nil
+}
+
+// afterCommit is called after HEAD moves from the previous commit. The caller must hold the gitLock
+func (s *ConfigServer) afterCommit(previous, commitId string) {
+	changed, deleted, err := s.GitRepo.ChangedFiles(previous, commitId)
+	if err != nil {
+		log.Printf("error listing changes in commit %s: %v", commitId, err)
+	} else {
+		s.watchers.publish(commitId, changed, deleted)
+	}
+	if s.PushMode == pushOnCommit {
+		if err := s.pushIfNeeded(); err != nil {
+			// The commit is saved locally, and will be pushed with the next commit.
+			log.Printf("error pushing changes to git %v", err)
+		}
+	}
+}
+
+// pushIfNeeded pushes to the upstream if there are local commits that have not been pushed.
+// The caller must hold the gitLock
+func (s *ConfigServer) pushIfNeeded() error {
+	needsPush, err := s.GitRepo.NeedsPush()
+	if err != nil || !needsPush {
+		return err
+	}
+	return s.GitRepo.Push()
+}
+
+// pull brings the branch up to date with the upstream, and tells watchers about the changes. Every product is
+// locked while pending updates are committed and the branch is pulled, so no update writes files the pull
+// could overwrite, or leaves uncommitted changes that stop the pull.
+func (s *ConfigServer) pull() (string, string, error) {
+	unlock := s.products.lockAll()
+	defer unlock()
+	s.flushAllLocked()
+	s.gitLock.Lock()
+	defer s.gitLock.Unlock()
+	before, after, err := s.GitRepo.Pull()
+	if err != nil {
+		return before, after, err
+	}
+	if after != before {
+		s.afterCommit(before, after)
+	}
+	return before, after, nil
+}
+
+// pullLoop periodically pulls changes from the upstream.
+func (s *ConfigServer) pullLoop(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		if _, _, err := s.pull(); err != nil {
+			// A conflict needs someone to fix the upstream branch. Local commits are kept.
+			log.Printf("error pulling changes from git %v", err)
+		}
+	}
+}
+
+// pushLoop periodically pushes new commits to the upstream.
+func (s *ConfigServer) pushLoop(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		s.gitLock.Lock()
+		if err := s.pushIfNeeded(); err != nil {
+			log.Printf("error pushing changes to git %v", err)
+		}
+		s.gitLock.Unlock()
+	}
 }
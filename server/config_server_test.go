@@ -0,0 +1,151 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	f "github.com/ForgeRock/configsaver/internal/fileutils"
+	"github.com/ForgeRock/configsaver/internal/git"
+	pb "github.com/ForgeRock/configsaver/proto"
+	g "github.com/libgit2/git2go/v31"
+)
+
+// The am configuration of the default cdk profile
+const amPath = "docker/am/config-profiles/cdk"
+
+// The am.json the test repository starts with
+const initialAm = `{"a": 1, "b": 1}`
+
+// newTestRepo creates a repository with am configuration on master, for the test server to clone
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := g.InitRepository(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Free()
+	name := amPath + "/am.json"
+	if err = os.MkdirAll(filepath.Join(dir, amPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, name), []byte(initialAm), 0644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := repo.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Free()
+	if err = index.AddByPath(name); err != nil {
+		t.Fatal(err)
+	}
+	treeId, err := index.WriteTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := repo.LookupTree(treeId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Free()
+	sig := &g.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	if _, err = repo.CreateCommit("HEAD", sig, sig, "initial", tree); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// newTestServer clones a test repository, and serves it from the autosave branch. Updates are batched for the
+// quiet period, if it is not 0.
+func newTestServer(t *testing.T, quiet time.Duration) *ConfigServer {
+	t.Helper()
+	t.Setenv("GIT_REPO", newTestRepo(t))
+	t.Setenv("GIT_SSH_PATH", "")
+	root := filepath.Join(t.TempDir(), "repo")
+	repo, err := git.OpenGitRepo(root, "autosave", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &ConfigServer{RootDirectory: root, FileUtil: f.NewFileUtil(root), GitRepo: repo, PushMode: pushOnDemand}
+	s.commits = newCommitQueue(&s.gitLock)
+	s.batches = newCommitBatcher(quiet, time.Minute)
+	s.setServerConfig(defaultServerConfig())
+	return s
+}
+
+// updateAm sends an am update from the pod, based on the commit
+func updateAm(t *testing.T, s *ConfigServer, pod, commitId string, files map[string][]byte) string {
+	t.Helper()
+	tarBytes, err := f.CreateTarBufferFromFiles(files, f.Uncompressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := s.UpdateConfig(context.Background(), &pb.UpdateConfigRequest{
+		ProductId: "am",
+		CommitId:  commitId,
+		ConfigTar: tarBytes,
+		PodName:   pod,
+	})
+	if err != nil {
+		t.Fatalf("UpdateConfig() = %v", err)
+	}
+	return r.CommitId
+}
+
+// getAm returns the am files at the commit, and the commit id they were read from
+func getAm(t *testing.T, s *ConfigServer, commitId string) (map[string][]byte, string) {
+	t.Helper()
+	r, err := s.GetConfig(context.Background(), &pb.GetConfigRequest{ProductId: "am", CommitId: commitId})
+	if err != nil {
+		t.Fatalf("GetConfig() = %v", err)
+	}
+	files, err := f.ReadTarBuffer(r.ConfigTar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files, r.CommitId
+}
+
+func TestLegacyMasterCommit(t *testing.T) {
+	s := newTestServer(t, 0)
+	_, initial := getAm(t, s, "")
+	updated := updateAm(t, s, "am-0", initial, map[string][]byte{"am.json": []byte(`{"a": 2, "b": 1}`)})
+
+	// master is behind the autosave branch, but older clients mean the current configuration
+	files, commitId := getAm(t, s, "master")
+	if commitId != updated {
+		t.Errorf("GetConfig(master) commit = %s, want the autosave HEAD %s", commitId, updated)
+	}
+	if got := string(files["am.json"]); got != `{"a": 2, "b": 1}` {
+		t.Errorf("GetConfig(master) am.json = %s, want the saved update", got)
+	}
+
+	// an update based on master is merged against the current configuration, so it does not conflict
+	updateAm(t, s, "am-1", "master", map[string][]byte{"am.json": []byte(`{"a": 2, "b": 3}`)})
+	files, _ = getAm(t, s, "")
+	if got := string(files["am.json"]); got != `{"a": 2, "b": 3}` {
+		t.Errorf("am.json = %s, want the update based on master", got)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	git "github.com/ForgeRock/configsaver/internal/git"
+	"github.com/ForgeRock/configsaver/internal/merge"
+	pb "github.com/ForgeRock/configsaver/proto"
+)
+
+// Conflicts are sent to the client in the error details, in the response trailer. The values of a conflict are
+// left out if any is larger than maxConflictValue, or once the values of all conflicts reach maxConflictValues.
+const (
+	maxConflictValue  = 64 * 1024
+	maxConflictValues = 256 * 1024
+)
+
+// conflictError is returned when an update makes changes that conflict with changes made on the server
+// since the commit the update is based on
+type conflictError struct {
+	// the current commit on the server
+	commitId  string
+	conflicts []*pb.Conflict
+}
+
+func (e *conflictError) Error() string {
+	paths := make([]string, 0, len(e.conflicts))
+	for _, c := range e.conflicts {
+		if c.JsonPointer != "" {
+			paths = append(paths, c.Path+"#"+c.JsonPointer)
+		} else {
+			paths = append(paths, c.Path)
+		}
+	}
+	return fmt.Sprintf("changes conflict with the server since the base commit: %s", strings.Join(paths, ", "))
+}
+
+// details returns the conflicts for the client
+func (e *conflictError) details() *pb.ConflictDetails {
+	return &pb.ConflictDetails{CommitId: e.commitId, Conflicts: e.conflicts}
+}
+
+// mergeUpdate merges an update based on the base commit with the changes made to the product configuration
+// since. Files changed on both sides are merged: JSON files key by key, other files line by line. Returns the
+// files to write, with merged contents. Changes that can not be merged are returned as a conflictError.
+// The caller must hold the gitLock
+func (s *ConfigServer) mergeUpdate(base, productPath string, files map[string][]byte, deleted []string) (map[string][]byte, error) {
+	head, err := s.GitRepo.HeadCommitId()
+	if err != nil {
+		return nil, err
+	}
+	changed, removed, err := s.GitRepo.ChangedFiles(base, head)
+	if err != nil {
+		return nil, err
+	}
+	upstream := make(map[string]bool)
+	for _, name := range append(filesUnder(productPath, changed), filesUnder(productPath, removed)...) {
+		upstream[name] = true
+	}
+	if len(upstream) == 0 {
+		return files, nil
+	}
+
+	_, headFiles, err := s.GitRepo.TreeFiles(head, productPath)
+	if err != nil && !errors.Is(err, git.ErrPathNotFound) {
+		return nil, err
+	}
+	_, baseFiles, err := s.GitRepo.TreeFiles(base, productPath)
+	if err != nil && !errors.Is(err, git.ErrPathNotFound) {
+		return nil, err
+	}
+
+	merged := make(map[string][]byte, len(files))
+	conflicts := make([]*pb.Conflict, 0)
+	for name, ours := range files {
+		theirs, theirsOk := headFiles[name]
+		if !upstream[name] || (theirsOk && bytes.Equal(ours, theirs)) {
+			merged[name] = ours
+			continue
+		}
+		baseData, baseOk := baseFiles[name]
+		if !theirsOk {
+			// modified here, deleted on the server
+			conflicts = append(conflicts, newConflict(name, "", baseData, ours, nil))
+			continue
+		}
+		data, fileConflicts, err := mergeFile(name, baseData, baseOk, ours, theirs)
+		if err != nil {
+			return nil, err
+		}
+		merged[name] = data
+		conflicts = append(conflicts, fileConflicts...)
+	}
+	for _, name := range deleted {
+		// a file deleted on both sides is not a conflict
+		if theirs, ok := headFiles[name]; upstream[name] && ok {
+			conflicts = append(conflicts, newConflict(name, "", baseFiles[name], nil, theirs))
+		}
+	}
+	if len(conflicts) == 0 {
+		return merged, nil
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool { return conflicts[i].Path < conflicts[j].Path })
+	limitConflictValues(conflicts)
+	return nil, &conflictError{commitId: head, conflicts: conflicts}
+}
+
+// mergeFile merges the changes made to a file on both sides. JSON files are merged key by key, falling
+// back to a text merge if a side is not valid JSON.
+func mergeFile(name string, base []byte, baseOk bool, ours, theirs []byte) ([]byte, []*pb.Conflict, error) {
+	conflicts := make([]*pb.Conflict, 0)
+	if strings.HasSuffix(name, ".json") {
+		var baseJSON []byte
+		if baseOk {
+			baseJSON = base
+		}
+		data, jsonConflicts, err := merge.JSON(baseJSON, ours, theirs)
+		if err == nil {
+			for _, c := range jsonConflicts {
+				conflicts = append(conflicts, newConflict(name, c.Pointer, []byte(c.Base), []byte(c.Ours), []byte(c.Theirs)))
+			}
+			return data, conflicts, nil
+		}
+		log.Printf("can not merge %s as json, merging as text: %v", name, err)
+	}
+
+	data, clean, err := git.MergeText(name, base, ours, theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !clean {
+		conflicts = append(conflicts, newConflict(name, "", base, ours, theirs))
+	}
+	return data, conflicts, nil
+}
+
+// newConflict returns a conflict with its values. Proto strings must be UTF-8, so the values are left out if a side
+// is binary, such as a keystore, or too large to send.
+func newConflict(name, pointer string, base, ours, theirs []byte) *pb.Conflict {
+	c := &pb.Conflict{Path: name, JsonPointer: pointer}
+	for _, value := range [][]byte{base, ours, theirs} {
+		if len(value) > maxConflictValue || !utf8.Valid(value) {
+			c.ContentsOmitted = true
+			return c
+		}
+	}
+	c.Base, c.Ours, c.Theirs = string(base), string(ours), string(theirs)
+	return c
+}
+
+// limitConflictValues leaves out the values of the conflicts that do not fit in maxConflictValues
+func limitConflictValues(conflicts []*pb.Conflict) {
+	total := 0
+	for _, c := range conflicts {
+		total += len(c.Base) + len(c.Ours) + len(c.Theirs)
+		if total > maxConflictValues {
+			c.Base, c.Ours, c.Theirs = "", "", ""
+			c.ContentsOmitted = true
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"errors"
+	"log"
+
+	f "github.com/ForgeRock/configsaver/internal/fileutils"
+	git "github.com/ForgeRock/configsaver/internal/git"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+)
+
+// Domain for the ErrorInfo details attached to errors returned to clients
+const errorDomain = "configsaver.forgerock.com"
+
+// Reasons reported in the ErrorInfo details
+const (
+	reasonUnknownProduct    = "UNKNOWN_PRODUCT"
+	reasonProfileNotAllowed = "PROFILE_NOT_ALLOWED"
+	reasonInvalidProfile    = "INVALID_PROFILE"
+	reasonRevisionNotFound  = "REVISION_NOT_FOUND"
+	reasonPathNotFound      = "PATH_NOT_FOUND"
+	reasonInvalidArchive    = "INVALID_ARCHIVE"
+	reasonUnsafeArchive     = "UNSAFE_ARCHIVE"
+	reasonInvalidStream     = "INVALID_STREAM"
+	reasonChecksumMismatch  = "CHECKSUM_MISMATCH"
+	reasonInvalidPageToken  = "INVALID_PAGE_TOKEN"
+	reasonValidationFailed  = "VALIDATION_FAILED"
+	reasonConflict          = "CONFLICT"
+	reasonPushRejected      = "PUSH_REJECTED"
+	reasonPushFailed        = "PUSH_FAILED"
+	reasonPullFailed        = "PULL_FAILED"
+	reasonRepoLocked        = "REPO_LOCKED"
+	reasonWatchLagging      = "WATCH_LAGGING"
+	reasonInternal          = "INTERNAL"
+)
+
+// statusError converts an error to a gRPC status error. The status code tells the client whether
+// retrying the call may succeed. The product id, if any, is returned to the client in the ErrorInfo details.
+func statusError(err error, productId string) error {
+	var verr *validationError
+	if errors.As(err, &verr) {
+		return newStatusError(codes.InvalidArgument, reasonValidationFailed, err.Error(), productId, verr.badRequest())
+	}
+	var aerr *f.UnsafeArchiveError
+	if errors.As(err, &aerr) {
+		return newStatusError(codes.InvalidArgument, reasonUnsafeArchive, err.Error(), productId, unsafeEntries(aerr))
+	}
+	var cerr *conflictError
+	if errors.As(err, &cerr) {
+		return newStatusError(codes.FailedPrecondition, reasonConflict, err.Error(), productId, cerr.details())
+	}
+
+	var serr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &serr) {
+		// already a status error, for example from a stream
+		return serr.GRPCStatus().Err()
+	}
+
+	code, reason := codes.Internal, reasonInternal
+	switch {
+	case errors.Is(err, f.ErrChecksumMismatch):
+		// the tar file was changed in transit. Sending it again may succeed
+		code, reason = codes.DataLoss, reasonChecksumMismatch
+	case errors.Is(err, git.ErrRevisionNotFound):
+		code, reason = codes.NotFound, reasonRevisionNotFound
+	case errors.Is(err, git.ErrPathNotFound):
+		code, reason = codes.NotFound, reasonPathNotFound
+	case errors.Is(err, f.ErrInvalidArchive):
+		code, reason = codes.InvalidArgument, reasonInvalidArchive
+	case errors.Is(err, git.ErrConflict):
+		code, reason = codes.FailedPrecondition, reasonConflict
+	case errors.Is(err, git.ErrPushRejected):
+		code, reason = codes.FailedPrecondition, reasonPushRejected
+	case errors.Is(err, git.ErrLocked):
+		// another git process is using the repo. Retrying later should succeed
+		code, reason = codes.Unavailable, reasonRepoLocked
+	}
+	return newStatusError(code, reason, err.Error(), productId)
+}
+
+// newStatusError creates a gRPC status error with ErrorInfo details, followed by any extra details
+func newStatusError(code codes.Code, reason, message, productId string, details ...protoiface.MessageV1) error {
+	log.Printf("returning error %s %s: %s", code, reason, message)
+	info := &errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	}
+	if productId != "" {
+		info.Metadata = map[string]string{"product_id": productId}
+	}
+	st := status.New(code, message)
+	detailed, err := st.WithDetails(append([]protoiface.MessageV1{info}, details...)...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// unsafeEntries returns the rejected archive entries as error details for the client
+func unsafeEntries(e *f.UnsafeArchiveError) *errdetails.BadRequest {
+	br := &errdetails.BadRequest{}
+	for name, problem := range e.Entries {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: name, Description: problem})
+	}
+	return br
+}
@@ -0,0 +1,204 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"log"
+	"sort"
+	"sync"
+
+	f "github.com/ForgeRock/configsaver/internal/fileutils"
+	pb "github.com/ForgeRock/configsaver/proto"
+)
+
+// The most blob checksums kept. The cache is cleared when it is full.
+const maxBlobHashes = 100000
+
+// blobHashes caches the SHA-256 checksum of git blobs, so comparing a manifest only reads the files that
+// changed since they were last hashed. A blob's contents never change, so the entries never go stale.
+type blobHashes struct {
+	lock sync.Mutex
+	sums map[string][]byte
+}
+
+func (h *blobHashes) get(blobId string) ([]byte, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	sum, ok := h.sums[blobId]
+	return sum, ok
+}
+
+func (h *blobHashes) put(blobId string, sum []byte) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.sums == nil || len(h.sums) >= maxBlobHashes {
+		h.sums = make(map[string][]byte)
+	}
+	h.sums[blobId] = sum
+}
+
+// manifestDiff is the difference between a client's manifest and a product's files in a commit
+type manifestDiff struct {
+	commitId string
+	// files that are not in the manifest or differ from it, with their blob ids
+	changed map[string]string
+	// contents of the changed files that were read to hash them
+	contents map[string][]byte
+	// manifest files that are not in the commit
+	removed []string
+}
+
+// diffManifest compares the manifest with the product's files at the revision. Ignored files are left out.
+// If rev is HEAD, the caller must hold the product lock.
+func (s *ConfigServer) diffManifest(product *ProductConfig, productPath, rev string, manifest []*pb.FileHash) (*manifestDiff, error) {
+	s.gitLock.Lock()
+	defer s.gitLock.Unlock()
+	commitId, blobs, err := s.GitRepo.TreeBlobs(rev, productPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make(map[string][]byte, len(manifest))
+	for _, file := range manifest {
+		sums[file.Path] = file.Sha256
+	}
+	d := &manifestDiff{commitId: commitId, changed: make(map[string]string), contents: make(map[string][]byte)}
+	for name, blobId := range blobs {
+		if product.ignored(name) {
+			continue
+		}
+		clientSum, ok := sums[name]
+		if !ok {
+			d.changed[name] = blobId
+			continue
+		}
+		sum, ok := s.blobHashes.get(blobId)
+		if !ok {
+			data, err := s.GitRepo.ReadBlob(blobId)
+			if err != nil {
+				return nil, err
+			}
+			hash := sha256.Sum256(data)
+			sum = hash[:]
+			s.blobHashes.put(blobId, sum)
+			d.contents[name] = data
+		}
+		if !bytes.Equal(sum, clientSum) {
+			d.changed[name] = blobId
+		}
+	}
+	for name := range sums {
+		if _, ok := blobs[name]; !ok && !product.ignored(name) {
+			d.removed = append(d.removed, name)
+		}
+	}
+	sort.Strings(d.removed)
+	return d, nil
+}
+
+// readChanged reads the contents of the changed files
+func (s *ConfigServer) readChanged(d *manifestDiff) (map[string][]byte, error) {
+	s.gitLock.Lock()
+	defer s.gitLock.Unlock()
+	files := make(map[string][]byte, len(d.changed))
+	for name, blobId := range d.changed {
+		if data, ok := d.contents[name]; ok {
+			files[name] = data
+			continue
+		}
+		data, err := s.GitRepo.ReadBlob(blobId)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// readManifestConfig returns the product files that differ from the request's manifest, the manifest files that
+// were removed, and the commit they were read from.
+func (s *ConfigServer) readManifestConfig(in *pb.GetConfigRequest, product *ProductConfig, productPath string) (string, map[string][]byte, []string, error) {
+	rev := in.CommitId
+	if rev == "" {
+		// Commit any pending updates, so HEAD has the product's current files
+		if err := s.flushProduct(productPath); err != nil {
+			return "", nil, nil, err
+		}
+		unlock := s.products.rlock(productPath)
+		defer unlock()
+		rev = "HEAD"
+	}
+	d, err := s.diffManifest(product, productPath, rev, in.Manifest)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	files, err := s.readChanged(d)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	log.Printf("manifest of %d files: sending %d, %d removed", len(in.Manifest), len(files), len(d.removed))
+	return d.commitId, files, d.removed, nil
+}
+
+// CompareManifest tells the client which of its files the server does not have, so it only uploads those.
+func (s *ConfigServer) CompareManifest(ctx context.Context, in *pb.CompareManifestRequest) (*pb.CompareManifestReply, error) {
+	log.Printf("CompareManifest product: %s profile: %s files: %d", in.ProductId, in.Profile, len(in.Manifest))
+	product, productPath, err := s.resolveProduct(in.ProductId, in.Profile)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(in.Manifest))
+	for _, file := range in.Manifest {
+		paths = append(paths, file.Path)
+	}
+	if _, err = f.CheckPaths(paths); err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+	if err = s.flushProduct(productPath); err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+	unlock := s.products.rlock(productPath)
+	defer unlock()
+	d, err := s.diffManifest(product, productPath, "HEAD", in.Manifest)
+	if err != nil {
+		return nil, statusError(err, in.ProductId)
+	}
+
+	reply := &pb.CompareManifestReply{CommitId: d.commitId, ChangedFiles: d.removed}
+	for _, file := range in.Manifest {
+		if _, ok := d.changed[file.Path]; ok {
+			reply.ChangedFiles = append(reply.ChangedFiles, file.Path)
+		}
+	}
+	inManifest := make(map[string]bool, len(in.Manifest))
+	for _, path := range paths {
+		inManifest[path] = true
+	}
+	for name := range d.changed {
+		if !inManifest[name] {
+			reply.MissingFiles = append(reply.MissingFiles, name)
+		}
+	}
+	sort.Strings(reply.ChangedFiles)
+	sort.Strings(reply.MissingFiles)
+	return reply, nil
+}
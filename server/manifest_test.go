@@ -0,0 +1,117 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"reflect"
+	"sort"
+	"testing"
+
+	f "github.com/ForgeRock/configsaver/internal/fileutils"
+	pb "github.com/ForgeRock/configsaver/proto"
+)
+
+// testManifest returns the manifest of the files
+func testManifest(files map[string]string) []*pb.FileHash {
+	manifest := make([]*pb.FileHash, 0, len(files))
+	for name, data := range files {
+		sum := sha256.Sum256([]byte(data))
+		manifest = append(manifest, &pb.FileHash{Path: name, Sha256: sum[:]})
+	}
+	return manifest
+}
+
+func sorted(names []string) []string {
+	names = append([]string{}, names...)
+	sort.Strings(names)
+	return names
+}
+
+func TestCompareManifest(t *testing.T) {
+	tests := []struct {
+		name string
+		// the client's files
+		files                    map[string]string
+		wantChanged, wantMissing []string
+	}{
+		{name: "unchanged", files: map[string]string{"am.json": initialAm}},
+		{name: "modified while stopped", files: map[string]string{"am.json": "{}"}, wantChanged: []string{"am.json"}},
+		{name: "added while stopped", files: map[string]string{"am.json": initialAm, "new.json": "{}"}, wantChanged: []string{"new.json"}},
+		{name: "deleted while stopped", files: map[string]string{"new.json": "{}"}, wantChanged: []string{"new.json"}, wantMissing: []string{"am.json"}},
+	}
+	s := newTestServer(t, 0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := s.CompareManifest(context.Background(), &pb.CompareManifestRequest{ProductId: "am", Manifest: testManifest(tt.files)})
+			if err != nil {
+				t.Fatalf("CompareManifest() = %v", err)
+			}
+			if got := sorted(r.ChangedFiles); !reflect.DeepEqual(got, sorted(tt.wantChanged)) {
+				t.Errorf("CompareManifest() changed %v, want %v", got, tt.wantChanged)
+			}
+			if got := sorted(r.MissingFiles); !reflect.DeepEqual(got, sorted(tt.wantMissing)) {
+				t.Errorf("CompareManifest() missing %v, want %v", got, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestGetConfigManifest(t *testing.T) {
+	tests := []struct {
+		name string
+		// the client's files, nil if its directory is not dedicated to the configuration and it sends no manifest
+		files       map[string]string
+		wantFiles   []string
+		wantDeleted []string
+	}{
+		{name: "unchanged", files: map[string]string{"am.json": initialAm}},
+		{name: "changed on the server", files: map[string]string{"am.json": "{}"}, wantFiles: []string{"am.json"}},
+		{name: "added on the server", files: map[string]string{"new.json": "{}"}, wantFiles: []string{"am.json"}, wantDeleted: []string{"new.json"}},
+		{name: "not dedicated", wantFiles: []string{"am.json"}},
+	}
+	s := newTestServer(t, 0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &pb.GetConfigRequest{ProductId: "am"}
+			if tt.files != nil {
+				in.Manifest = testManifest(tt.files)
+			}
+			r, err := s.GetConfig(context.Background(), in)
+			if err != nil {
+				t.Fatalf("GetConfig() = %v", err)
+			}
+			files, err := f.ReadTarBuffer(r.ConfigTar)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, 0, len(files))
+			for name := range files {
+				names = append(names, name)
+			}
+			if got := sorted(names); !reflect.DeepEqual(got, sorted(tt.wantFiles)) {
+				t.Errorf("GetConfig() files %v, want %v", got, tt.wantFiles)
+			}
+			if got := sorted(r.DeletedFiles); !reflect.DeepEqual(got, sorted(tt.wantDeleted)) {
+				t.Errorf("GetConfig() deleted %v, want %v", got, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// How many files of each kind (added, modified, deleted) are listed in a commit message
+const maxListedFiles = 50
+
+// updateSummary lists the files a commit adds, modifies and deletes, relative to the product path
+type updateSummary struct {
+	added, modified, deleted []string
+}
+
+func (u *updateSummary) empty() bool {
+	return len(u.added) == 0 && len(u.modified) == 0 && len(u.deleted) == 0
+}
+
+// changeSummary lists the files under the product path that will be committed: the changes in the working tree
+// since HEAD. The caller must hold the gitLock
+func (s *ConfigServer) changeSummary(productPath string) (*updateSummary, error) {
+	added, modified, deleted, err := s.GitRepo.Changes(productPath)
+	if err != nil {
+		return nil, err
+	}
+	summary := &updateSummary{
+		added:    filesUnder(productPath, added),
+		modified: filesUnder(productPath, modified),
+		deleted:  filesUnder(productPath, deleted),
+	}
+	sort.Strings(summary.added)
+	sort.Strings(summary.modified)
+	sort.Strings(summary.deleted)
+	return summary, nil
+}
+
+// A trailer is a "Key: value" line at the end of a commit message
+type trailer struct {
+	key, value string
+}
+
+// commitMessage builds a commit message from the subject, the files changed and the trailers.
+// Trailers with an empty value are left out.
+func commitMessage(subject string, summary *updateSummary, trailers ...trailer) string {
+	var b strings.Builder
+	b.WriteString(subject)
+	b.WriteString("\n")
+	listFiles(&b, "Added", summary.added)
+	listFiles(&b, "Modified", summary.modified)
+	listFiles(&b, "Deleted", summary.deleted)
+
+	b.WriteString("\n")
+	for _, t := range trailers {
+		if t.value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", t.key, t.value)
+		}
+	}
+	return b.String()
+}
+
+func listFiles(b *strings.Builder, heading string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", heading)
+	for i, file := range files {
+		if i == maxListedFiles {
+			fmt.Fprintf(b, "  ... and %d more\n", len(files)-maxListedFiles)
+			break
+		}
+		fmt.Fprintf(b, "  %s\n", file)
+	}
+}
@@ -0,0 +1,231 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ServerConfig is loaded from the file in CONFIG_FILE. The file can be YAML or JSON. Example:
+//
+//	defaultProfile: cdk
+//	products:
+//	  am:
+//	    path: docker/am/config-profiles
+//	    profiles: [cdk, mini]
+//	    ignore: ["*.bak"]
+//	    validators: [json]
+type ServerConfig struct {
+	// The profile used for products that do not set their own default
+	DefaultProfile string `json:"defaultProfile,omitempty"`
+	// Products keyed by product id (am, idm, ig, ds, or any custom product)
+	Products map[string]*ProductConfig `json:"products"`
+}
+
+// ProductConfig describes where to find a product's configuration in the git repo
+type ProductConfig struct {
+	// Path relative to the root of the repo. If the product has profiles, this is the directory containing
+	// the profile directories (example, docker/am/config-profiles). Otherwise it is the configuration directory.
+	Path string `json:"path"`
+	// The profiles clients are allowed to use (example, cdk, mini)
+	Profiles []string `json:"profiles,omitempty"`
+	// The profile to use, if not the server default
+	DefaultProfile string `json:"defaultProfile,omitempty"`
+	// Files matching these patterns are not saved or returned to clients. Patterns are matched
+	// against the file path relative to the configuration directory, and the file name.
+	Ignore []string `json:"ignore,omitempty"`
+	// Validators that updated files must pass before they are saved (see validators.go)
+	Validators []string `json:"validators,omitempty"`
+}
+
+// defaultServerConfig is used when CONFIG_FILE is not set
+func defaultServerConfig() *ServerConfig {
+	return &ServerConfig{
+		DefaultProfile: "cdk",
+		Products: map[string]*ProductConfig{
+			"am":  {Path: "docker/am/config-profiles", Profiles: []string{"cdk"}},
+			"idm": {Path: "docker/idm/config-profiles", Profiles: []string{"cdk"}},
+		},
+	}
+}
+
+// loadServerConfig reads and validates the server configuration file
+func loadServerConfig(file string) (*ServerConfig, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &ServerConfig{}
+	// YAML is a superset of JSON, so this handles both
+	if err = yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", file, err)
+	}
+	if err = cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration in %s: %v", file, err)
+	}
+	return cfg, nil
+}
+
+func (cfg *ServerConfig) validate() error {
+	if len(cfg.Products) == 0 {
+		return fmt.Errorf("no products are defined")
+	}
+	for id, p := range cfg.Products {
+		if p == nil || p.Path == "" {
+			return fmt.Errorf("product %s has no path", id)
+		}
+		if path.IsAbs(p.Path) || path.Clean(p.Path) != p.Path || strings.HasPrefix(p.Path, "..") {
+			return fmt.Errorf("product %s path %s must be a clean path relative to the root of the repo", id, p.Path)
+		}
+		if len(p.Profiles) > 0 && !contains(p.Profiles, cfg.defaultProfile(p)) {
+			return fmt.Errorf("product %s default profile %s is not one of its profiles %v", id, cfg.defaultProfile(p), p.Profiles)
+		}
+		for _, pattern := range p.Ignore {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("product %s ignore pattern %s: %v", id, pattern, err)
+			}
+		}
+		for _, v := range p.Validators {
+			if _, ok := validators[v]; !ok {
+				return fmt.Errorf("product %s has unknown validator %s", id, v)
+			}
+		}
+	}
+	return nil
+}
+
+// defaultProfile returns the profile to use for a product
+func (cfg *ServerConfig) defaultProfile(p *ProductConfig) string {
+	if p.DefaultProfile != "" {
+		return p.DefaultProfile
+	}
+	return cfg.DefaultProfile
+}
+
+// productIds returns the sorted list of product ids
+func (cfg *ServerConfig) productIds() []string {
+	ids := make([]string, 0, len(cfg.Products))
+	for id := range cfg.Products {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// configPath returns the directory with the product's configuration for the profile
+func (p *ProductConfig) configPath(profile string) string {
+	if len(p.Profiles) == 0 {
+		return p.Path
+	}
+	return path.Join(p.Path, profile)
+}
+
+// filter removes ignored files from an update
+func (p *ProductConfig) filter(files map[string][]byte, deleted []string) (map[string][]byte, []string) {
+	kept := make(map[string][]byte, len(files))
+	for name, data := range files {
+		if p.ignored(name) {
+			log.Printf("ignoring %s", name)
+			continue
+		}
+		kept[name] = data
+	}
+	return kept, p.unignored(deleted)
+}
+
+// unignored returns the files that do not match an ignore pattern
+func (p *ProductConfig) unignored(files []string) []string {
+	kept := make([]string, 0, len(files))
+	for _, name := range files {
+		if !p.ignored(name) {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// ignored returns true if the file (relative to the configuration directory) matches an ignore pattern
+func (p *ProductConfig) ignored(file string) bool {
+	for _, pattern := range p.Ignore {
+		if ok, _ := path.Match(pattern, file); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(file)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// watchServerConfig reloads the configuration file on SIGHUP, or when the file changes.
+// If the new file is not valid the current configuration is kept.
+func (s *ConfigServer) watchServerConfig(file string, pollInterval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastModified := modTime(file)
+	for {
+		select {
+		case <-hup:
+			log.Printf("SIGHUP received, reloading %s", file)
+		case <-ticker.C:
+			t := modTime(file)
+			if t.Equal(lastModified) {
+				continue
+			}
+			log.Printf("%s changed, reloading", file)
+		}
+		lastModified = modTime(file)
+		cfg, err := loadServerConfig(file)
+		if err != nil {
+			log.Printf("could not reload configuration, keeping the current configuration: %v", err)
+			continue
+		}
+		s.setServerConfig(cfg)
+	}
+}
+
+func modTime(file string) time.Time {
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"sort"
+	"sync"
+)
+
+// How many commits can wait in the queue before callers block
+const commitQueueSize = 64
+
+// commitQueue makes changes to the repository one at a time, in the order they were submitted.
+// Each job holds the repo lock while it runs.
+type commitQueue struct {
+	jobs chan func()
+}
+
+func newCommitQueue(repoLock sync.Locker) *commitQueue {
+	q := &commitQueue{jobs: make(chan func(), commitQueueSize)}
+	go func() {
+		for job := range q.jobs {
+			repoLock.Lock()
+			job()
+			repoLock.Unlock()
+		}
+	}()
+	return q
+}
+
+// run queues the job and waits for it to finish
+func (q *commitQueue) run(job func() (string, error)) (string, error) {
+	var commitId string
+	var err error
+	done := make(chan struct{})
+	q.jobs <- func() {
+		defer close(done)
+		commitId, err = job()
+	}
+	<-done
+	return commitId, err
+}
+
+// productLocks protects the files of each product in the working tree. Updates hold the write lock while
+// they write files and commit them, so reads see either all or none of an update. Products are
+// independent, so an update to one product does not wait for another product's files to be written.
+// A pull changes the files of every product, so it locks them all with lockAll.
+type productLocks struct {
+	// held for reading with any product lock, and for writing by lockAll
+	all     sync.RWMutex
+	mapLock sync.Mutex
+	paths   map[string]*sync.RWMutex
+}
+
+func (p *productLocks) get(path string) *sync.RWMutex {
+	p.mapLock.Lock()
+	defer p.mapLock.Unlock()
+	if p.paths == nil {
+		p.paths = make(map[string]*sync.RWMutex)
+	}
+	l, ok := p.paths[path]
+	if !ok {
+		l = &sync.RWMutex{}
+		p.paths[path] = l
+	}
+	return l
+}
+
+// lock locks the product paths for writing, and returns the function that unlocks them. Paths are locked
+// in order, so updates that lock more than one path do not deadlock.
+func (p *productLocks) lock(paths ...string) func() {
+	sorted := append([]string{}, paths...)
+	sort.Strings(sorted)
+	p.all.RLock()
+	locks := make([]*sync.RWMutex, 0, len(sorted))
+	for i, path := range sorted {
+		if i > 0 && path == sorted[i-1] {
+			continue
+		}
+		l := p.get(path)
+		l.Lock()
+		locks = append(locks, l)
+	}
+	return func() {
+		for _, l := range locks {
+			l.Unlock()
+		}
+		p.all.RUnlock()
+	}
+}
+
+// rlock locks the product path for reading, and returns the function that unlocks it
+func (p *productLocks) rlock(path string) func() {
+	p.all.RLock()
+	l := p.get(path)
+	l.RLock()
+	return func() {
+		l.RUnlock()
+		p.all.RUnlock()
+	}
+}
+
+// lockAll locks every product for writing, and returns the function that unlocks them. A product lock must not
+// be held by the caller.
+func (p *productLocks) lockAll() func() {
+	p.all.Lock()
+	return p.all.Unlock
+}
@@ -0,0 +1,103 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"log"
+
+	f "github.com/ForgeRock/configsaver/internal/fileutils"
+	pb "github.com/ForgeRock/configsaver/proto"
+	"google.golang.org/grpc/codes"
+)
+
+// GetConfigStream returns the same configuration as GetConfig. The files are read into memory, as they are for
+// GetConfig, but the tar file is written straight to the stream in chunks. It is not built in memory, and may be
+// larger than the gRPC message size limit.
+func (s *ConfigServer) GetConfigStream(in *pb.GetConfigRequest, stream pb.ConfigSaver_GetConfigStreamServer) error {
+	log.Printf("GetConfigStream product: %s profile: %s commit: %s", in.ProductId, in.Profile, in.CommitId)
+	commitId, files, removed, err := s.readConfig(in)
+	if err != nil {
+		return err
+	}
+	compression := chooseCompression(in.AcceptCompression)
+	header := &pb.GetConfigReply{
+		CommitId:            commitId,
+		Compression:         compression,
+		AcceptedCompression: acceptedCompression,
+		DeletedFiles:        removed,
+	}
+	if err := stream.Send(&pb.GetConfigChunk{Chunk: &pb.GetConfigChunk_Header{Header: header}}); err != nil {
+		return err
+	}
+
+	w := f.NewChunkWriter(func(data []byte) error {
+		return stream.Send(&pb.GetConfigChunk{Chunk: &pb.GetConfigChunk_Data{Data: data}})
+	})
+	if err := f.WriteTarFromFiles(w, files, f.Compression(compression)); err != nil {
+		return statusError(err, in.ProductId)
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	log.Printf("sent %s tar file with %d bytes from commit %s", f.Compression(compression), w.Size(), commitId)
+	trailer := &pb.ChunkTrailer{Sha256: w.Sum(), Size: w.Size()}
+	return stream.Send(&pb.GetConfigChunk{Chunk: &pb.GetConfigChunk_Trailer{Trailer: trailer}})
+}
+
+// UpdateConfigStream saves an update in the same way as UpdateConfig, with the tar file received in chunks.
+// Nothing is saved unless the tar file matches the checksum in the trailer.
+func (s *ConfigServer) UpdateConfigStream(stream pb.ConfigSaver_UpdateConfigStreamServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	in := first.GetHeader()
+	if in == nil {
+		return newStatusError(codes.InvalidArgument, reasonInvalidStream, "the first message must be the header", "")
+	}
+	if len(in.ConfigTar) > 0 {
+		return newStatusError(codes.InvalidArgument, reasonInvalidStream, "the tar file must be sent in data chunks", in.ProductId)
+	}
+	log.Printf("UpdateConfigStream product: %s profile: %s commit: %s", in.ProductId, in.Profile, in.CommitId)
+
+	r := f.NewChunkReader(func() (f.Chunk, error) {
+		m, err := stream.Recv()
+		if err != nil {
+			return f.Chunk{}, err
+		}
+		switch chunk := m.Chunk.(type) {
+		case *pb.UpdateConfigChunk_Data:
+			return f.Chunk{Data: chunk.Data}, nil
+		case *pb.UpdateConfigChunk_Trailer:
+			return f.Chunk{Last: true, Sha256: chunk.Trailer.Sha256, Size: chunk.Trailer.Size}, nil
+		}
+		return f.Chunk{}, newStatusError(codes.InvalidArgument, reasonInvalidStream, "expected a data chunk or the trailer", in.ProductId)
+	})
+	files, err := f.ReadTar(r)
+	if err != nil {
+		log.Printf("could not read streamed tar file: %v\n", err)
+		return statusError(err, in.ProductId)
+	}
+
+	reply, err := s.saveConfig(in, files)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(reply)
+}
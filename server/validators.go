@@ -0,0 +1,87 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// A validator checks an updated file before it is saved. It returns an error describing any problem.
+type validator func(file string, data []byte) error
+
+// validators available to products in the server configuration file
+var validators = map[string]validator{
+	// json files must be well formed
+	"json": validateJSON,
+}
+
+func validateJSON(file string, data []byte) error {
+	if path.Ext(file) != ".json" {
+		return nil
+	}
+	var v interface{}
+	return json.Unmarshal(data, &v)
+}
+
+// validationError lists the files that failed validation
+type validationError struct {
+	// problem keyed by file name
+	failures map[string]string
+}
+
+func (e *validationError) Error() string {
+	files := make([]string, 0, len(e.failures))
+	for file, problem := range e.failures {
+		files = append(files, fmt.Sprintf("%s: %s", file, problem))
+	}
+	sort.Strings(files)
+	return "validation failed for " + strings.Join(files, ", ")
+}
+
+// badRequest returns the failures as error details for the client
+func (e *validationError) badRequest() *errdetails.BadRequest {
+	br := &errdetails.BadRequest{}
+	for file, problem := range e.failures {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: file, Description: problem})
+	}
+	return br
+}
+
+// validate runs the product validators on the updated files
+func (p *ProductConfig) validate(files map[string][]byte) error {
+	failures := make(map[string]string)
+	for _, name := range p.Validators {
+		v := validators[name]
+		for file, data := range files {
+			if err := v(file, data); err != nil {
+				failures[file] = fmt.Sprintf("%s: %v", name, err)
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return &validationError{failures}
+	}
+	return nil
+}
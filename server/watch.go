@@ -0,0 +1,163 @@
+/*
+ *
+ * Copyright  2021 ForgeRock AS
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	pb "github.com/ForgeRock/configsaver/proto"
+	"google.golang.org/grpc/codes"
+)
+
+// How many events are queued for a watcher before it is dropped. A dropped client reconnects and
+// reads the full configuration.
+const watchQueueSize = 32
+
+// A watcher is a WatchConfig call waiting for changes under a product configuration directory
+type watcher struct {
+	path   string
+	events chan *pb.ConfigEvent
+}
+
+// watchers tracks the open WatchConfig calls
+type watchers struct {
+	lock    sync.Mutex
+	members map[*watcher]bool
+}
+
+func (w *watchers) add(path string) *watcher {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.members == nil {
+		w.members = make(map[*watcher]bool)
+	}
+	member := &watcher{path: path, events: make(chan *pb.ConfigEvent, watchQueueSize)}
+	w.members[member] = true
+	return member
+}
+
+func (w *watchers) remove(member *watcher) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.members[member] {
+		delete(w.members, member)
+		close(member.events)
+	}
+}
+
+// publish sends the files changed by a commit to the watchers of the directories they are in.
+// Paths are relative to the root of the repo. Publishing never blocks: a watcher that is too far
+// behind is removed, which ends its call.
+func (w *watchers) publish(commitId string, changed, deleted []string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for member := range w.members {
+		event := &pb.ConfigEvent{
+			CommitId:     commitId,
+			ChangedFiles: filesUnder(member.path, changed),
+			DeletedFiles: filesUnder(member.path, deleted),
+		}
+		if len(event.ChangedFiles) == 0 && len(event.DeletedFiles) == 0 {
+			continue
+		}
+		select {
+		case member.events <- event:
+		default:
+			log.Printf("watcher of %s is not keeping up, closing it", member.path)
+			delete(w.members, member)
+			close(member.events)
+		}
+	}
+}
+
+// filesUnder returns the files in the directory dir, relative to dir
+func filesUnder(dir string, files []string) []string {
+	result := make([]string, 0)
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	for _, file := range files {
+		if strings.HasPrefix(file, prefix) {
+			result = append(result, strings.TrimPrefix(file, prefix))
+		}
+	}
+	return result
+}
+
+// WatchConfig streams an event to the client each time a commit changes the product configuration.
+func (s *ConfigServer) WatchConfig(in *pb.WatchConfigRequest, stream pb.ConfigSaver_WatchConfigServer) error {
+	log.Printf("WatchConfig product: %s profile: %s commit: %s", in.ProductId, in.Profile, in.CommitId)
+	product, productPath, err := s.resolveProduct(in.ProductId, in.Profile)
+	if err != nil {
+		return err
+	}
+
+	// Start watching before looking for missed changes, so no commit falls in between.
+	member := s.watchers.add(productPath)
+	defer s.watchers.remove(member)
+
+	if commitId := requestedCommit(in.CommitId); commitId != "" {
+		event, err := s.changesSince(commitId, productPath)
+		if err != nil {
+			return statusError(err, in.ProductId)
+		}
+		if err = sendEvent(stream, product, event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-member.events:
+			if !ok {
+				return newStatusError(codes.Aborted, reasonWatchLagging, "the client did not keep up with configuration changes", in.ProductId)
+			}
+			if err := sendEvent(stream, product, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendEvent sends the event to the client, leaving out files the product ignores. Nothing is sent
+// if no files are left.
+func sendEvent(stream pb.ConfigSaver_WatchConfigServer, product *ProductConfig, event *pb.ConfigEvent) error {
+	changed, deleted := product.unignored(event.ChangedFiles), product.unignored(event.DeletedFiles)
+	if len(changed) == 0 && len(deleted) == 0 {
+		return nil
+	}
+	return stream.Send(&pb.ConfigEvent{CommitId: event.CommitId, ChangedFiles: changed, DeletedFiles: deleted})
+}
+
+// changesSince returns the changes made under dir between the commit and the current HEAD
+func (s *ConfigServer) changesSince(commitId, dir string) (*pb.ConfigEvent, error) {
+	s.gitLock.Lock()
+	defer s.gitLock.Unlock()
+	head, err := s.GitRepo.HeadCommitId()
+	if err != nil {
+		return nil, err
+	}
+	changed, deleted, err := s.GitRepo.ChangedFiles(commitId, head)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ConfigEvent{CommitId: head, ChangedFiles: filesUnder(dir, changed), DeletedFiles: filesUnder(dir, deleted)}, nil
+}